@@ -0,0 +1,153 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+)
+
+const (
+	advisoryStateFileName = "advisories.json"
+	feedTypeAdvisory      = "advisory"
+	maxAdvisoryTitleLen   = 60
+)
+
+var severityPattern = regexp.MustCompile(`(?i)\b(critical|high|moderate|medium|important|low)\b`)
+
+// AdvisoryEntry is the stored snapshot of one advisory
+type AdvisoryEntry struct {
+	Title    string `json:"title"`
+	Link     string `json:"link"`
+	Severity string `json:"severity"`
+}
+
+// AdvisoryChange is an addition or severity change found by diffing a feed
+type AdvisoryChange struct {
+	ID          string
+	Entry       AdvisoryEntry
+	OldSeverity string
+}
+
+// advisoryID prefers the feed GUID, which stays stable when titles are edited
+func advisoryID(item *gofeed.Item) string {
+	if item.GUID != "" {
+		return item.GUID
+	}
+	return item.Link
+}
+
+// advisorySeverity reads the severity from categories first, then from the text
+func advisorySeverity(item *gofeed.Item) string {
+	for _, category := range item.Categories {
+		if match := severityPattern.FindString(category); match != "" {
+			return strings.ToUpper(match)
+		}
+	}
+	if match := severityPattern.FindString(item.Title + " " + item.Description); match != "" {
+		return strings.ToUpper(match)
+	}
+	return "UNKNOWN"
+}
+
+func readAdvisoryState(filename string) (map[string]map[string]AdvisoryEntry, error) {
+	state := make(map[string]map[string]AdvisoryEntry)
+
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", filename, err)
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+	return state, nil
+}
+
+func writeAdvisoryState(filename string, state map[string]map[string]AdvisoryEntry) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding advisory state: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", filename, err)
+	}
+	return nil
+}
+
+// diffAdvisories compares the current feed against the stored snapshot. Advisories
+// that dropped out of the feed are kept in the snapshot so they are not reported
+// as new if they reappear.
+func diffAdvisories(previous map[string]AdvisoryEntry, items []*gofeed.Item) ([]AdvisoryChange, map[string]AdvisoryEntry) {
+	current := make(map[string]AdvisoryEntry, len(previous)+len(items))
+	for id, entry := range previous {
+		current[id] = entry
+	}
+
+	var changes []AdvisoryChange
+	for _, item := range items {
+		id := advisoryID(item)
+		entry := AdvisoryEntry{Title: item.Title, Link: item.Link, Severity: advisorySeverity(item)}
+		old, existed := previous[id]
+		current[id] = entry
+
+		switch {
+		case !existed:
+			changes = append(changes, AdvisoryChange{ID: id, Entry: entry})
+		case old.Severity != entry.Severity:
+			changes = append(changes, AdvisoryChange{ID: id, Entry: entry, OldSeverity: old.Severity})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool { return changes[i].ID < changes[j].ID })
+	return changes, current
+}
+
+// formatAdvisoryTable renders all changes of a feed as one compact message
+func formatAdvisoryTable(feedURL string, changes []AdvisoryChange) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "📋 Advisory changes: %s (%d)\n", getDomain(feedURL), len(changes))
+	for _, change := range changes {
+		title := change.Entry.Title
+		if len(title) > maxAdvisoryTitleLen {
+			title = title[:maxAdvisoryTitleLen-1] + "…"
+		}
+		if change.OldSeverity == "" {
+			fmt.Fprintf(&b, "NEW  %-8s %s\n     %s\n", change.Entry.Severity, title, change.Entry.Link)
+		} else {
+			fmt.Fprintf(&b, "SEV  %s→%s %s\n     %s\n", change.OldSeverity, change.Entry.Severity, title, change.Entry.Link)
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// processAdvisoryFeed diffs an advisory feed against stored state and returns the
+// notification to send, or "" when nothing changed. The first sighting of a feed
+// only records a baseline so existing advisories are not flooded into the channel.
+func processAdvisoryFeed(stateFile, feedURL string, items []*gofeed.Item) (string, error) {
+	state, err := readAdvisoryState(stateFile)
+	if err != nil {
+		return "", err
+	}
+
+	previous, known := state[feedURL]
+	changes, current := diffAdvisories(previous, items)
+	state[feedURL] = current
+
+	if !dryRun {
+		if err := writeAdvisoryState(stateFile, state); err != nil {
+			return "", err
+		}
+	}
+
+	if !known || len(changes) == 0 {
+		return "", nil
+	}
+	return formatAdvisoryTable(feedURL, changes), nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// followedAuthorTag routes articles notified only because of their author
+const followedAuthorTag = "followed"
+
+// AuthorRule marks a researcher to always notify regardless of keywords, or to
+// never notify; authors are matched case-insensitively on the feed item's
+// author names and the extracted byline
+type AuthorRule struct {
+	Always bool `yaml:"always"`
+	Never  bool `yaml:"never"`
+}
+
+// itemAuthors collects the distinct author names a feed item and its page
+// carry. Sources join several authors with ", ", so those are split again.
+func itemAuthors(item *gofeed.Item, extracted *ExtractedContent) []string {
+	var candidates []string
+	if item.Author != nil {
+		candidates = append(candidates, item.Author.Name)
+	}
+	for _, author := range item.Authors {
+		if author != nil {
+			candidates = append(candidates, author.Name)
+		}
+	}
+	if extracted != nil {
+		candidates = append(candidates, extracted.Author)
+	}
+
+	var names []string
+	seen := make(map[string]struct{})
+	for _, candidate := range candidates {
+		for _, name := range strings.Split(candidate, ", ") {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if _, exists := seen[strings.ToLower(name)]; exists {
+				continue
+			}
+			seen[strings.ToLower(name)] = struct{}{}
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// authorRuleFor returns the rule for the first listed author of an item
+func authorRuleFor(names []string) (string, AuthorRule, bool) {
+	for _, name := range names {
+		for listed, rule := range settings.Authors {
+			if strings.EqualFold(name, listed) {
+				return listed, rule, true
+			}
+		}
+	}
+	return "", AuthorRule{}, false
+}
@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/fatih/color"
+	"github.com/mmcdole/gofeed"
+)
+
+const backfillTimeout = 30 * time.Second
+
+// runBackfill crawls a blog's archive and records historical articles in the
+// store and found-url list of each profile selected with --profile, without
+// notifying, so they are never sent later:
+//
+//	backfill [-pages 10] [-delay 5s] <feed or blog URL>
+//
+// Feeds are paged WordPress-style (?paged=N); HTML pages follow rel="next".
+func runBackfill(args []string) error {
+	fs := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	maxPages := fs.Int("pages", 10, "maximum number of archive pages to crawl")
+	delay := fs.Duration("delay", 5*time.Second, "minimum delay between page requests")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("usage: backfill [-pages N] [-delay D] <url>")
+	}
+	start := fs.Arg(0)
+
+	// Every selected profile records the history, so none notifies it later
+	profiles, err := commandProfiles()
+	if err != nil {
+		return err
+	}
+	foundUrls := make([]map[string]struct{}, len(profiles))
+	for i, profile := range profiles {
+		if foundUrls[i], err = readFoundURLs(profile.stateFile(foundUrlsFileName)); err != nil {
+			return err
+		}
+	}
+
+	rateLimiter := NewRateLimiter(*delay, time.Second)
+	ingested := 0
+
+	pageURL := start
+	for page := 1; page <= *maxPages && pageURL != ""; page++ {
+		rateLimiter.Wait(getDomain(pageURL))
+		printStatus(fmt.Sprintf("Backfilling page %d: %s", page, pageURL), color.FgMagenta)
+
+		items, next, err := fetchArchivePage(pageURL, start, page)
+		if err != nil {
+			// WordPress answers past the last page with 404
+			printError(fmt.Sprintf("Stopping backfill at %s: %v", pageURL, err))
+			break
+		}
+
+		newOnPage := 0
+		for i, profile := range profiles {
+			var records []StoredArticle
+			for _, item := range items {
+				if item.Link == "" {
+					continue
+				}
+				if _, exists := foundUrls[i][item.Link]; exists {
+					continue
+				}
+				foundUrls[i][item.Link] = struct{}{}
+
+				article := processArticle(item, nil, profile.Keywords)
+				if article == nil {
+					article = &Article{Title: item.Title, Link: item.Link, Published: item.Published}
+				}
+				records = append(records, newStoredArticle(article, start, false))
+			}
+
+			if !dryRun {
+				for _, record := range records {
+					if err := saveURL(record.Link, profile.stateFile(foundUrlsFileName)); err != nil {
+						return err
+					}
+				}
+				if err := appendStoredArticles(profile.stateFile(articleStoreFileName), records...); err != nil {
+					return err
+				}
+			}
+			newOnPage = max(newOnPage, len(records))
+		}
+
+		if newOnPage == 0 {
+			printStatus("No new articles on this page, stopping", color.FgYellow)
+			break
+		}
+		ingested += newOnPage
+		pageURL = next
+	}
+
+	printStatus(fmt.Sprintf("Backfill ingested %d articles from %s", ingested, start), color.FgGreen)
+	return nil
+}
+
+// fetchArchivePage returns the articles on one archive page and the URL of the next page
+func fetchArchivePage(pageURL, start string, page int) ([]*gofeed.Item, string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), backfillTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("creating request: %w", err)
+	}
+	setUserAgent(req)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", &HTTPError{StatusCode: resp.StatusCode}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, "", fmt.Errorf("reading page: %w", err)
+	}
+
+	if feed, err := gofeed.NewParser().Parse(bytes.NewReader(body)); err == nil {
+		return feed.Items, pagedURL(start, page+1), nil
+	}
+
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, "", fmt.Errorf("parsing page: %w", err)
+	}
+	return archiveLinks(doc, pageURL), nextPageLink(doc, pageURL), nil
+}
+
+// pagedURL sets WordPress' paged query parameter on a feed URL
+func pagedURL(feedURL string, page int) string {
+	u, err := url.Parse(feedURL)
+	if err != nil {
+		return ""
+	}
+	query := u.Query()
+	query.Set("paged", strconv.Itoa(page))
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// archiveLinks collects same-host article links from headings and <article> elements
+func archiveLinks(doc *goquery.Document, pageURL string) []*gofeed.Item {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+
+	var items []*gofeed.Item
+	doc.Find("article a[href], h1 a[href], h2 a[href], h3 a[href]").Each(func(_ int, sel *goquery.Selection) {
+		href, _ := sel.Attr("href")
+		link, err := base.Parse(href)
+		if err != nil || link.Host != base.Host || link.Path == base.Path || link.Path == "/" {
+			return
+		}
+		link.Fragment = ""
+
+		title := strings.Join(strings.Fields(sel.Text()), " ")
+		if title == "" {
+			return
+		}
+		items = append(items, &gofeed.Item{Title: title, Link: link.String()})
+	})
+	return items
+}
+
+// nextPageLink resolves the rel="next" pagination link, if any
+func nextPageLink(doc *goquery.Document, pageURL string) string {
+	href, exists := doc.Find(`link[rel="next"], a[rel="next"]`).First().Attr("href")
+	if !exists {
+		return ""
+	}
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return ""
+	}
+	next, err := base.Parse(href)
+	if err != nil {
+		return ""
+	}
+	return next.String()
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	backoffStateFileName = "rate-limits.json"
+
+	// A throttled domain is requested at most every throttleDelay (doubling on
+	// repeated 429/503s up to maxThrottleDelay) for throttleWindow, which
+	// outlasts the run so the next one does not re-trigger the ban
+	throttleWindow   = 6 * time.Hour
+	maxThrottleDelay = 2 * time.Minute
+)
+
+// DomainBackoff is an elevated request delay for a domain that answered 429/503
+type DomainBackoff struct {
+	Delay time.Duration `json:"delay"`
+	Until time.Time     `json:"until"`
+}
+
+// Throttle raises the delay between requests to a rate limiting domain
+func (r *RateLimiter) Throttle(domain string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	backoff := r.backoff[domain]
+	backoff.Delay = max(backoff.Delay*2, r.minDelay*4)
+	if backoff.Delay > maxThrottleDelay {
+		backoff.Delay = maxThrottleDelay
+	}
+	backoff.Until = time.Now().Add(throttleWindow)
+	r.backoff[domain] = backoff
+}
+
+// delayFor returns the minimum delay between requests to a domain; callers hold r.mu
+func (r *RateLimiter) delayFor(domain string) time.Duration {
+	if backoff, exists := r.backoff[domain]; exists {
+		if time.Now().Before(backoff.Until) && backoff.Delay > r.minDelay {
+			return backoff.Delay
+		}
+		delete(r.backoff, domain)
+	}
+	return r.minDelay
+}
+
+// RestoreBackoff loads the throttled domains saved by a previous run
+func (r *RateLimiter) RestoreBackoff(state map[string]DomainBackoff) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for domain, backoff := range state {
+		r.backoff[domain] = backoff
+	}
+}
+
+// BackoffState returns the throttles still in effect at now
+func (r *RateLimiter) BackoffState(now time.Time) map[string]DomainBackoff {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state := make(map[string]DomainBackoff)
+	for domain, backoff := range r.backoff {
+		if now.Before(backoff.Until) {
+			state[domain] = backoff
+		}
+	}
+	return state
+}
+
+// readBackoffState loads persisted throttles; a missing file is empty
+func readBackoffState(filename string) (map[string]DomainBackoff, error) {
+	state := make(map[string]DomainBackoff)
+
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", filename, err)
+	}
+
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+	return state, nil
+}
+
+func writeBackoffState(filename string, state map[string]DomainBackoff) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding rate limit state: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", filename, err)
+	}
+	return nil
+}
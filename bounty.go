@@ -0,0 +1,51 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// bountyPattern matches amounts such as "$5,000", "€10k", "$1.5K" or "USD 500"
+var bountyPattern = regexp.MustCompile(`(?i)(?:([$€£])\s?|\b(usd|eur|gbp)\s?)(\d{1,3}(?:,\d{3})+|\d+(?:\.\d+)?)\s?(k\b)?`)
+
+// BountySettings filters on the largest payout mentioned in an article.
+// Amounts are compared as written, without currency conversion.
+type BountySettings struct {
+	// Min drops articles whose largest amount is below it
+	Min float64 `yaml:"min"`
+	// Require also drops articles that mention no amount at all
+	Require bool `yaml:"require"`
+}
+
+// Bounty is the largest amount found in an article and how it was written
+type Bounty struct {
+	Amount float64
+	Text   string
+}
+
+// extractBounty returns the largest amount in the text, or nil
+func extractBounty(text string) *Bounty {
+	var best *Bounty
+	for _, match := range bountyPattern.FindAllStringSubmatch(text, -1) {
+		amount, err := strconv.ParseFloat(strings.ReplaceAll(match[3], ",", ""), 64)
+		if err != nil {
+			continue
+		}
+		if match[4] != "" {
+			amount *= 1000
+		}
+		if best == nil || amount > best.Amount {
+			best = &Bounty{Amount: amount, Text: strings.TrimSpace(match[0])}
+		}
+	}
+	return best
+}
+
+// bountyAllowed applies the minimum-bounty filter
+func bountyAllowed(bounty *Bounty) bool {
+	if bounty == nil {
+		return !settings.Bounty.Require
+	}
+	return bounty.Amount >= settings.Bounty.Min
+}
@@ -0,0 +1,56 @@
+package main
+
+import "testing"
+
+func TestExtractBounty(t *testing.T) {
+	tests := []struct {
+		text       string
+		wantAmount float64
+		wantText   string
+	}{
+		{"No payout mentioned", 0, ""},
+		{"Awarded $500 for an IDOR", 500, "$500"},
+		{"Paid $5,000 after triage", 5000, "$5,000"},
+		{"A €10k bounty", 10000, "€10k"},
+		{"Got $1.5K", 1500, "$1.5K"},
+		{"USD 750 for the report", 750, "USD 750"},
+		{"£ 200 then $3,000 for the chain", 3000, "$3,000"},
+		// A k inside a word is not a multiplier
+		{"$20 kits", 20, "$20"},
+	}
+	for _, tt := range tests {
+		got := extractBounty(tt.text)
+		if tt.wantText == "" {
+			if got != nil {
+				t.Errorf("extractBounty(%q) = %+v, want nil", tt.text, got)
+			}
+			continue
+		}
+		if got == nil || got.Amount != tt.wantAmount || got.Text != tt.wantText {
+			t.Errorf("extractBounty(%q) = %+v, want %v %q", tt.text, got, tt.wantAmount, tt.wantText)
+		}
+	}
+}
+
+func TestBountyAllowed(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings BountySettings
+		bounty   *Bounty
+		want     bool
+	}{
+		{"no filter", BountySettings{}, nil, true},
+		{"no amount", BountySettings{Min: 1000}, nil, true},
+		{"no amount required", BountySettings{Require: true}, nil, false},
+		{"below minimum", BountySettings{Min: 1000}, &Bounty{Amount: 500}, false},
+		{"at minimum", BountySettings{Min: 1000}, &Bounty{Amount: 1000}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useSettings(t, Settings{Bounty: tt.settings})
+			if got := bountyAllowed(tt.bounty); got != tt.want {
+				t.Errorf("bountyAllowed(%+v) = %v, want %v", tt.bounty, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/fatih/color"
+)
+
+const defaultBurst = 10
+
+// pendingNotification is an article waiting for the end of its feed
+type pendingNotification struct {
+	Tag     string
+	Article *Article
+}
+
+// burstLimit is how many notifications a feed sends one by one
+func burstLimit() int {
+	if settings.Telegram.Burst == 0 {
+		return defaultBurst
+	}
+	return settings.Telegram.Burst
+}
+
+// sendFeedNotifications sends a feed's notifications individually or, past
+// the burst limit, as one list message per tag
+func sendFeedNotifications(ctx context.Context, p Profile, botToken, feedURL string, pending []pendingNotification) {
+	if limit := burstLimit(); limit < 0 || len(pending) <= limit {
+		for _, n := range pending {
+			notification := articleNotification(n.Article, n.Tag)
+			n.Article.Messages = append(n.Article.Messages, p.send(ctx, botToken, n.Tag, notification)...)
+			printSuccess(notification.Text)
+		}
+		return
+	}
+
+	byTag := make(map[string][]DigestEntry)
+	articlesByTag := make(map[string][]*Article)
+	for _, n := range pending {
+		byTag[n.Tag] = append(byTag[n.Tag], DigestEntry{Tag: n.Tag, Title: n.Article.Title, Link: n.Article.Link})
+		articlesByTag[n.Tag] = append(articlesByTag[n.Tag], n.Article)
+	}
+	tags := make([]string, 0, len(byTag))
+	for tag := range byTag {
+		tags = append(tags, tag)
+	}
+	sort.Strings(tags)
+
+	printStatus(fmt.Sprintf("Grouping %d notifications from %s", len(pending), getDomain(feedURL)), color.FgYellow)
+	f := messageFormatter{mode: settings.Telegram.ParseMode}
+	for _, tag := range tags {
+		title := fmt.Sprintf("📚 %d new %s writeups from %s", len(byTag[tag]), tag, getDomain(feedURL))
+		// Every article in a grouped message shares its ID
+		for _, message := range formatDigest(f, title, byTag[tag]) {
+			sent := p.send(ctx, botToken, tag, Notification{Text: message, ParseMode: f.mode})
+			for _, article := range articlesByTag[tag] {
+				article.Messages = append(article.Messages, sent...)
+			}
+			printSuccess(message)
+		}
+	}
+}
+
+// processedArticle is a matched article waiting to be stored and marked
+// once its feed's notifications are out
+type processedArticle struct {
+	article  *Article
+	dedupKey string
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// captureStdout returns what f prints to standard output
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = saved }()
+
+	output := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		output <- string(data)
+	}()
+	f()
+	w.Close()
+	return <-output
+}
+
+func TestSendFeedNotificationsBurst(t *testing.T) {
+	saved := dryRun
+	dryRun = true
+	t.Cleanup(func() { dryRun = saved })
+
+	pending := func(tags ...string) []pendingNotification {
+		var notifications []pendingNotification
+		for i, tag := range tags {
+			notifications = append(notifications, pendingNotification{
+				Tag:     tag,
+				Article: &Article{Title: fmt.Sprintf("Article %d", i), Link: fmt.Sprintf("https://example.com/%d", i)},
+			})
+		}
+		return notifications
+	}
+
+	tests := []struct {
+		name    string
+		burst   int
+		pending []pendingNotification
+		want    int
+	}{
+		{"below the default", 0, pending("xss", "xss", "idor"), 3},
+		{"past the limit", 2, pending("xss", "xss", "idor"), 2},
+		{"at the limit", 3, pending("xss", "xss", "idor"), 3},
+		{"never grouped", -1, pending("xss", "xss", "xss", "xss"), 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useSettings(t, Settings{Telegram: TelegramSettings{Burst: tt.burst}})
+			profile := Profile{Destinations: []Destination{{ChannelID: "-100"}}}
+
+			output := captureStdout(t, func() {
+				sendFeedNotifications(context.Background(), profile, "", "https://example.com/feed", tt.pending)
+			})
+			if got := strings.Count(output, "would send to chat -100"); got != tt.want {
+				t.Errorf("sent %d messages, want %d:\n%s", got, tt.want, output)
+			}
+		})
+	}
+}
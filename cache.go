@@ -0,0 +1,97 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HTTPCacheSettings enables the on-disk response cache when Dir is set
+type HTTPCacheSettings struct {
+	Dir string `yaml:"dir"`
+}
+
+// cachedResponse is one cache file
+type cachedResponse struct {
+	URL     string    `json:"url"`
+	Expires time.Time `json:"expires"`
+	Body    []byte    `json:"body"`
+}
+
+func cacheFileFor(dir, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// loadCachedResponse returns the body stored for a URL while it is still fresh
+func loadCachedResponse(dir, key string, now time.Time) ([]byte, bool) {
+	if dir == "" {
+		return nil, false
+	}
+	data, err := os.ReadFile(cacheFileFor(dir, key))
+	if err != nil {
+		return nil, false
+	}
+	var cached cachedResponse
+	if err := json.Unmarshal(data, &cached); err != nil || cached.URL != key || now.After(cached.Expires) {
+		return nil, false
+	}
+	return cached.Body, true
+}
+
+// storeCachedResponse saves a body when its headers allow caching
+func storeCachedResponse(dir, key string, header http.Header, body []byte, now time.Time) error {
+	if dir == "" {
+		return nil
+	}
+	expires, ok := cacheExpiry(header, now)
+	if !ok {
+		return nil
+	}
+
+	data, err := json.Marshal(cachedResponse{URL: key, Expires: expires, Body: body})
+	if err != nil {
+		return fmt.Errorf("encoding cache entry: %w", err)
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating %s: %w", dir, err)
+	}
+	if err := os.WriteFile(cacheFileFor(dir, key), data, 0644); err != nil {
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+	return nil
+}
+
+// cacheExpiry derives freshness from Cache-Control max-age (preferred) or
+// Expires; no-store, no-cache and private responses are not cached
+func cacheExpiry(header http.Header, now time.Time) (time.Time, bool) {
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		directive = strings.ToLower(strings.TrimSpace(directive))
+		switch {
+		case directive == "no-store" || directive == "no-cache" || directive == "private":
+			return time.Time{}, false
+		case strings.HasPrefix(directive, "max-age="):
+			seconds, err := strconv.Atoi(strings.TrimPrefix(directive, "max-age="))
+			if err != nil || seconds <= 0 {
+				return time.Time{}, false
+			}
+			age, _ := strconv.Atoi(header.Get("Age"))
+			return now.Add(time.Duration(seconds-age) * time.Second), seconds > age
+		}
+	}
+
+	if expires := header.Get("Expires"); expires != "" {
+		t, err := http.ParseTime(expires)
+		if err == nil && t.After(now) {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Category groups synonymous keywords under one tag and destination thread.
+// A category that extends another inherits its terms, its exclusions and,
+// unless it sets its own, its thread.
+type Category struct {
+	Thread   string   `yaml:"thread"`
+	Terms    []string `yaml:"terms"`
+	Extends  string   `yaml:"extends"`
+	Disabled bool     `yaml:"disabled"`
+
+	// Exclude drops the category's match when the article also contains one
+	// of these terms (plain or /regex/), e.g. "course" for xss
+	Exclude []string `yaml:"exclude"`
+
+	// Match sets whole-word and case-sensitive matching for the category's terms
+	Match MatchOptions `yaml:"match"`
+}
+
+// Resolved category lookups, built from hunter.yaml at startup
+var (
+	categoryOfTerm  = map[string]string{}
+	categoryThreads = map[string]string{}
+	disabledTerms   = map[string]struct{}{}
+)
+
+// resolveCategories flattens inheritance into term→category and category→thread
+// lookups. Terms of disabled categories are returned separately so they can be
+// removed from matching even when they are also built-in keywords.
+func resolveCategories(categories map[string]Category) (terms, threads map[string]string, disabled map[string]struct{}, err error) {
+	terms = make(map[string]string)
+	threads = make(map[string]string)
+	disabled = make(map[string]struct{})
+
+	names := make([]string, 0, len(categories))
+	for name := range categories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		chain, err := categoryChain(categories, name)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		var thread string
+		var categoryTerms []string
+		for _, ancestor := range chain {
+			if thread == "" {
+				thread = categories[ancestor].Thread
+			}
+			categoryTerms = append(categoryTerms, categories[ancestor].Terms...)
+		}
+
+		if categories[name].Disabled {
+			for _, term := range categories[name].Terms {
+				disabled[term] = struct{}{}
+			}
+			continue
+		}
+
+		threads[name] = thread
+		for _, term := range categoryTerms {
+			// The first category (alphabetically) to claim a term keeps it
+			if _, exists := terms[term]; !exists {
+				terms[term] = name
+			}
+		}
+	}
+
+	return terms, threads, disabled, nil
+}
+
+// categoryChain lists a category followed by its ancestors
+func categoryChain(categories map[string]Category, name string) ([]string, error) {
+	var chain []string
+	visited := make(map[string]struct{})
+	for current := name; current != ""; current = categories[current].Extends {
+		if _, exists := categories[current]; !exists {
+			return nil, fmt.Errorf("category %q extends unknown category %q", chain[len(chain)-1], current)
+		}
+		if _, seen := visited[current]; seen {
+			return nil, fmt.Errorf("category inheritance cycle: %s", strings.Join(append(chain, current), " -> "))
+		}
+		visited[current] = struct{}{}
+		chain = append(chain, current)
+	}
+	return chain, nil
+}
+
+// applyCategories returns a copy of a keyword→thread table with category terms
+// added and terms of disabled categories removed
+func applyCategories(keywordThreads map[string]string) map[string]string {
+	merged := make(map[string]string, len(keywordThreads)+len(categoryOfTerm))
+	for term, thread := range keywordThreads {
+		merged[term] = thread
+	}
+	for term, category := range categoryOfTerm {
+		merged[term] = categoryThreads[category]
+	}
+	for term := range disabledTerms {
+		if _, enabled := categoryOfTerm[term]; !enabled {
+			delete(merged, term)
+		}
+	}
+	return merged
+}
+
+// excludedFor returns the exclusion terms of a tag: its keyword options,
+// then those of its category and the category's ancestors
+func excludedFor(tag string) []string {
+	exclude := settings.KeywordOptions[tag].Exclude
+	if _, exists := settings.Categories[tag]; !exists {
+		return exclude
+	}
+	chain, err := categoryChain(settings.Categories, tag)
+	if err != nil {
+		return exclude
+	}
+	exclude = append([]string(nil), exclude...)
+	for _, name := range chain {
+		exclude = append(exclude, settings.Categories[name].Exclude...)
+	}
+	return exclude
+}
+
+// excludedBy returns the first exclusion term found in the text, if any
+func excludedBy(text, lowerText string, exclude []string) (string, bool) {
+	for _, term := range exclude {
+		if matchesKeyword(text, lowerText, term) {
+			return term, true
+		}
+	}
+	return "", false
+}
+
+// tagFor returns the tag shown and routed for a matched term: its category
+// when it belongs to one, otherwise the term itself
+func tagFor(term string) string {
+	if category, exists := categoryOfTerm[term]; exists {
+		return category
+	}
+	return term
+}
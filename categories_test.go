@@ -0,0 +1,191 @@
+package main
+
+import (
+	"maps"
+	"strings"
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestResolveCategories(t *testing.T) {
+	categories := map[string]Category{
+		"web":   {Thread: "10", Terms: []string{"web"}},
+		"xss":   {Extends: "web", Terms: []string{"xss", "cross-site scripting"}},
+		"dom":   {Extends: "xss", Thread: "12", Terms: []string{"dom xss"}},
+		"cloud": {Thread: "20", Terms: []string{"aws", "web"}},
+		"old":   {Disabled: true, Thread: "30", Terms: []string{"flash", "xss"}},
+	}
+
+	terms, threads, disabled, err := resolveCategories(categories)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantTerms := map[string]string{
+		"aws": "cloud",
+		// cloud sorts before web and xss, so it claims the shared term
+		"web":                  "cloud",
+		"dom xss":              "dom",
+		"xss":                  "dom",
+		"cross-site scripting": "dom",
+	}
+	if !maps.Equal(terms, wantTerms) {
+		t.Errorf("terms = %v, want %v", terms, wantTerms)
+	}
+
+	wantThreads := map[string]string{"web": "10", "xss": "10", "dom": "12", "cloud": "20"}
+	if !maps.Equal(threads, wantThreads) {
+		t.Errorf("threads = %v, want %v", threads, wantThreads)
+	}
+
+	if _, exists := disabled["flash"]; !exists || len(disabled) != 2 {
+		t.Errorf("disabled = %v, want flash and xss", disabled)
+	}
+}
+
+func TestResolveCategoriesErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		categories map[string]Category
+		want       string
+	}{
+		{
+			name:       "unknown parent",
+			categories: map[string]Category{"xss": {Extends: "web"}},
+			want:       `category "xss" extends unknown category "web"`,
+		},
+		{
+			name: "cycle",
+			categories: map[string]Category{
+				"a": {Extends: "b"},
+				"b": {Extends: "a"},
+			},
+			want: "category inheritance cycle: a -> b -> a",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, _, err := resolveCategories(tt.categories)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("resolveCategories error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyCategories(t *testing.T) {
+	useSettings(t, Settings{Categories: map[string]Category{
+		"xss":   {Thread: "10", Terms: []string{"xss", "cross-site scripting"}},
+		"cloud": {Thread: "20", Terms: []string{"aws"}},
+		"old":   {Disabled: true, Terms: []string{"flash", "aws"}},
+	}})
+
+	got := applyCategories(map[string]string{
+		"xss":   "1",
+		"flash": "2",
+		"idor":  "3",
+	})
+	want := map[string]string{
+		"xss":                  "10",
+		"cross-site scripting": "10",
+		// Still enabled through cloud
+		"aws":  "20",
+		"idor": "3",
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("applyCategories = %v, want %v", got, want)
+	}
+}
+
+func TestTagFor(t *testing.T) {
+	useSettings(t, Settings{Categories: map[string]Category{
+		"xss": {Terms: []string{"cross-site scripting"}},
+	}})
+
+	tests := []struct {
+		term, want string
+	}{
+		{"cross-site scripting", "xss"},
+		{"idor", "idor"},
+	}
+	for _, tt := range tests {
+		if got := tagFor(tt.term); got != tt.want {
+			t.Errorf("tagFor(%q) = %q, want %q", tt.term, got, tt.want)
+		}
+	}
+}
+
+func TestExcludedBy(t *testing.T) {
+	useSettings(t, Settings{
+		Exclude: []string{"course"},
+		Categories: map[string]Category{
+			"web": {Terms: []string{"web"}, Exclude: []string{"/\\bctf\\b/"}},
+			"xss": {Extends: "web", Terms: []string{"xss"}, Exclude: []string{"tutorial"}},
+		},
+	})
+
+	tests := []struct {
+		text    string
+		exclude []string
+		want    string
+		found   bool
+	}{
+		{"XSS course for beginners", settings.Exclude, "course", true},
+		{"Stored XSS in a CMS", settings.Exclude, "", false},
+		{"XSS tutorial", excludedFor("xss"), "tutorial", true},
+		// Exclusions are inherited from the parent category
+		{"XSS in a CTF challenge", excludedFor("xss"), `/\bctf\b/`, true},
+		{"XSS in a CTF challenge", excludedFor("web"), `/\bctf\b/`, true},
+		{"Web tutorial", excludedFor("web"), "", false},
+		{"XSS tutorial", excludedFor("idor"), "", false},
+	}
+	for _, tt := range tests {
+		got, found := excludedBy(tt.text, strings.ToLower(tt.text), tt.exclude)
+		if got != tt.want || found != tt.found {
+			t.Errorf("excludedBy(%q, %v) = %q, %v, want %q, %v", tt.text, tt.exclude, got, found, tt.want, tt.found)
+		}
+	}
+}
+
+func TestKeywordExclusions(t *testing.T) {
+	useSettings(t, Settings{
+		KeywordOptions: map[string]MatchOptions{
+			"ssrf":                 {Exclude: []string{"roadmap"}},
+			"cross site scripting": {Exclude: []string{"cheat sheet"}},
+		},
+		Categories: map[string]Category{
+			"xss": {Terms: []string{"xss", "cross site scripting"}},
+		},
+	})
+	keywords := applyCategories(map[string]string{"ssrf": "5", "idor": "6"})
+
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"plain keyword", "SSRF in a PDF renderer", "ssrf"},
+		{"plain keyword excluded", "A beginner SSRF roadmap", ""},
+		{"exclusion is per keyword", "An IDOR and SSRF roadmap", "idor"},
+		{"synonym excluded", "Cross site scripting cheat sheet", ""},
+		// Another term of the category still matches
+		{"synonym excluded, other term matches", "Cross site scripting (XSS) cheat sheet", "xss"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			article := processArticle(&gofeed.Item{Title: tt.text, Link: "https://example.com/a"}, nil, keywords)
+			var got string
+			if article != nil {
+				got = strings.Join(article.Keywords, " ")
+			}
+			if got != tt.want {
+				t.Errorf("processArticle(%q) tags %q, want %q", tt.text, got, tt.want)
+			}
+		})
+	}
+
+	if got := excludedFor("ssrf"); len(got) != 1 || got[0] != "roadmap" {
+		t.Errorf("excludedFor(ssrf) = %v, want [roadmap]", got)
+	}
+}
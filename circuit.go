@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	circuitStateFileName    = "circuit-breaker.json"
+	defaultCircuitThreshold = 3
+)
+
+// CircuitBreakerSettings stops fetching from a domain after Threshold
+// consecutive failed feeds. With a Cooldown the domain also stays skipped on
+// later runs until the cool-down has passed.
+type CircuitBreakerSettings struct {
+	Threshold int           `yaml:"threshold"`
+	Cooldown  time.Duration `yaml:"cooldown"`
+}
+
+// CircuitBreaker tracks consecutive feed failures per domain
+type CircuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	failures  map[string]int
+	// openUntil is persisted; a zero time keeps the circuit open for this run only
+	openUntil map[string]time.Time
+}
+
+func newCircuitBreaker(cfg CircuitBreakerSettings, openUntil map[string]time.Time) *CircuitBreaker {
+	threshold := cfg.Threshold
+	if threshold <= 0 {
+		threshold = defaultCircuitThreshold
+	}
+	return &CircuitBreaker{
+		threshold: threshold,
+		cooldown:  cfg.Cooldown,
+		failures:  make(map[string]int),
+		openUntil: openUntil,
+	}
+}
+
+// Open reports whether feeds of the domain should be skipped
+func (c *CircuitBreaker) Open(domain string, now time.Time) bool {
+	until, exists := c.openUntil[domain]
+	if !exists {
+		return false
+	}
+	if until.IsZero() || now.Before(until) {
+		return true
+	}
+	delete(c.openUntil, domain)
+	return false
+}
+
+// Failure records a failed feed and reports whether it tripped the circuit
+func (c *CircuitBreaker) Failure(domain string, now time.Time) bool {
+	c.failures[domain]++
+	if c.failures[domain] < c.threshold {
+		return false
+	}
+	var until time.Time
+	if c.cooldown > 0 {
+		until = now.Add(c.cooldown)
+	}
+	c.openUntil[domain] = until
+	return true
+}
+
+// Success resets the domain's failure count
+func (c *CircuitBreaker) Success(domain string) {
+	delete(c.failures, domain)
+}
+
+// readCircuitState loads the domains still cooling down; a missing file is empty
+func readCircuitState(filename string) (map[string]time.Time, error) {
+	openUntil := make(map[string]time.Time)
+
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return openUntil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", filename, err)
+	}
+
+	if err := json.Unmarshal(data, &openUntil); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+	return openUntil, nil
+}
+
+// writeCircuitState saves the cool-downs that outlast this run
+func writeCircuitState(filename string, c *CircuitBreaker, now time.Time) error {
+	persisted := make(map[string]time.Time)
+	for domain, until := range c.openUntil {
+		if !until.IsZero() && now.Before(until) {
+			persisted[domain] = until
+		}
+	}
+
+	data, err := json.MarshalIndent(persisted, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding circuit breaker state: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", filename, err)
+	}
+	return nil
+}
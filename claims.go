@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+const (
+	claimsFileName       = "claims.json"
+	defaultClaimDeadline = 48 * time.Hour
+	// The daily digest escalates critical articles this recent that nobody claimed
+	unclaimedCriticalWindow = 7 * 24 * time.Hour
+)
+
+// Claim records a team member taking an article for review
+type Claim struct {
+	Link         string    `json:"link"`
+	Claimant     string    `json:"claimant"`
+	ClaimedAt    time.Time `json:"claimed_at"`
+	Deadline     time.Time `json:"deadline"`
+	Done         bool      `json:"done,omitempty"`
+	LastReminder time.Time `json:"last_reminder,omitempty"`
+}
+
+// Overdue reports whether an open claim has passed its review deadline
+func (c *Claim) Overdue(now time.Time) bool {
+	return !c.Done && now.After(c.Deadline)
+}
+
+func readClaims(filename string) (map[string]*Claim, error) {
+	claims := make(map[string]*Claim)
+
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return claims, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", filename, err)
+	}
+
+	if err := json.Unmarshal(data, &claims); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+	return claims, nil
+}
+
+func writeClaims(filename string, claims map[string]*Claim) error {
+	data, err := json.MarshalIndent(claims, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding claims: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", filename, err)
+	}
+	return nil
+}
+
+// runClaim handles the triage subcommands. Claims are kept per profile, so
+// add and done need --profile when several profiles are configured.
+//
+//	claim add <link> <who> [deadline]   e.g. "claim add https://... alice 24h"
+//	claim done <link>
+//	claim list
+//	claim remind                        posts reminders for overdue claims
+func runClaim(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: claim add|done|list|remind")
+	}
+	now := time.Now()
+
+	switch args[0] {
+	case "add":
+		if len(args) < 3 {
+			return fmt.Errorf("usage: claim add <link> <who> [deadline]")
+		}
+		deadline := defaultClaimDeadline
+		if len(args) > 3 {
+			var err error
+			if deadline, err = time.ParseDuration(args[3]); err != nil {
+				return fmt.Errorf("parsing deadline: %w", err)
+			}
+		}
+		return updateClaims(func(claims map[string]*Claim) error {
+			claims[args[1]] = &Claim{
+				Link:      args[1],
+				Claimant:  args[2],
+				ClaimedAt: now,
+				Deadline:  now.Add(deadline),
+			}
+			printStatus(fmt.Sprintf("%s claimed %s until %s", args[2], args[1], now.Add(deadline).Format("2006-01-02 15:04")), color.FgGreen)
+			return nil
+		})
+	case "done":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: claim done <link>")
+		}
+		return updateClaims(func(claims map[string]*Claim) error {
+			claim, exists := claims[args[1]]
+			if !exists {
+				return fmt.Errorf("no claim for %s", args[1])
+			}
+			claim.Done = true
+			return nil
+		})
+	case "list":
+		profiles, err := commandProfiles()
+		if err != nil {
+			return err
+		}
+		for _, profile := range profiles {
+			claims, err := readClaims(profile.stateFile(claimsFileName))
+			if err != nil {
+				return err
+			}
+			for _, claim := range sortedClaims(claims) {
+				state := "open"
+				switch {
+				case claim.Done:
+					state = "done"
+				case claim.Overdue(now):
+					state = "OVERDUE"
+				}
+				fmt.Printf("%s%-8s %-12s %s  %s\n", profile.label(), state, claim.Claimant, claim.Deadline.Format("2006-01-02 15:04"), claim.Link)
+			}
+		}
+		return nil
+	case "remind":
+		return remindOverdueClaims(now)
+	}
+	return fmt.Errorf("unknown claim command %q", args[0])
+}
+
+// updateClaims applies a change to the claims of the one selected profile
+func updateClaims(change func(claims map[string]*Claim) error) error {
+	profiles, err := commandProfiles()
+	if err != nil {
+		return err
+	}
+	if len(profiles) != 1 {
+		return fmt.Errorf("claims are kept per profile: choose one with --profile")
+	}
+
+	filename := profiles[0].stateFile(claimsFileName)
+	claims, err := readClaims(filename)
+	if err != nil {
+		return err
+	}
+	if err := change(claims); err != nil {
+		return err
+	}
+	return writeClaims(filename, claims)
+}
+
+// remindOverdueClaims posts the overdue claims of each profile to its
+// destinations, reminding of each claim at most once a day
+func remindOverdueClaims(now time.Time) error {
+	botToken := getEnvForEnvironment("TELEGRAM_BOT_TOKEN")
+	channelID := getEnvForEnvironment("TELEGRAM_CHANNEL_ID")
+	if botToken == "" || channelID == "" {
+		return fmt.Errorf("TELEGRAM_BOT_TOKEN and TELEGRAM_CHANNEL_ID must be set to send reminders")
+	}
+
+	profiles, err := selectProfiles(settings.Profiles, profileName, channelID)
+	if err != nil {
+		return err
+	}
+	var failed []string
+	for _, profile := range profiles {
+		filename := profile.stateFile(claimsFileName)
+		claims, err := readClaims(filename)
+		if err != nil {
+			return err
+		}
+
+		var due []*Claim
+		var lines []string
+		for _, claim := range sortedClaims(claims) {
+			if !claim.Overdue(now) || now.Sub(claim.LastReminder) < 24*time.Hour {
+				continue
+			}
+			due = append(due, claim)
+			lines = append(lines, fmt.Sprintf("• %s (%s, due %s)", claim.Link, claim.Claimant,
+				claim.Deadline.Format("2006-01-02 15:04")))
+		}
+
+		if len(lines) == 0 {
+			printStatus(profile.label()+"No overdue claims", color.FgYellow)
+			continue
+		}
+
+		// A reminder that did not go out is retried by the next remind
+		message := fmt.Sprintf("%s⏰ Overdue reviews (%d):\n%s", profile.label(), len(lines), strings.Join(lines, "\n"))
+		if len(profile.send(context.Background(), botToken, "general", Notification{Text: message})) == 0 {
+			failed = append(failed, fmt.Sprintf("%sreminder for %d overdue claim(s) was not delivered", profile.label(), len(due)))
+			continue
+		}
+		printSuccess(message)
+
+		for _, claim := range due {
+			claim.LastReminder = now
+		}
+		if err := writeClaims(filename, claims); err != nil {
+			return err
+		}
+	}
+	if len(failed) > 0 {
+		return errors.New(strings.Join(failed, "; "))
+	}
+	return nil
+}
+
+// unclaimedCritical lists the stored critical articles of the escalation
+// window that have no claim, oldest first
+func unclaimedCritical(records []StoredArticle, claims map[string]*Claim, now time.Time) []DigestEntry {
+	var entries []DigestEntry
+	for _, record := range records {
+		if record.Severity != "critical" || now.Sub(record.IngestedAt) > unclaimedCriticalWindow {
+			continue
+		}
+		if _, claimed := claims[record.Link]; claimed {
+			continue
+		}
+		entries = append(entries, DigestEntry{Tag: record.Severity, Title: record.Title, Link: record.Link})
+	}
+	return entries
+}
+
+// profileUnclaimedCritical reads a profile's store and claims for the digest
+func profileUnclaimedCritical(p Profile, now time.Time) []DigestEntry {
+	records, err := readStoredArticles(p.stateFile(articleStoreFileName))
+	if err != nil {
+		printError(err.Error())
+		return nil
+	}
+	claims, err := readClaims(p.stateFile(claimsFileName))
+	if err != nil {
+		printError(err.Error())
+		return nil
+	}
+	return unclaimedCritical(records, claims, now)
+}
+
+func sortedClaims(claims map[string]*Claim) []*Claim {
+	sorted := make([]*Claim, 0, len(claims))
+	for _, claim := range claims {
+		sorted = append(sorted, claim)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Deadline.Before(sorted[j].Deadline)
+	})
+	return sorted
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUnclaimedCritical(t *testing.T) {
+	now := time.Date(2025, 1, 15, 8, 0, 0, 0, time.UTC)
+	records := []StoredArticle{
+		{Link: "https://example.com/unclaimed", Title: "Pre-auth RCE", Severity: "critical", IngestedAt: now.Add(-time.Hour)},
+		{Link: "https://example.com/claimed", Title: "Account takeover", Severity: "critical", IngestedAt: now.Add(-time.Hour)},
+		{Link: "https://example.com/done", Title: "Command injection", Severity: "critical", IngestedAt: now.Add(-time.Hour)},
+		{Link: "https://example.com/high", Title: "SSRF", Severity: "high", IngestedAt: now.Add(-time.Hour)},
+		{Link: "https://example.com/old", Title: "Old RCE", Severity: "critical", IngestedAt: now.Add(-unclaimedCriticalWindow - time.Hour)},
+	}
+	claims := map[string]*Claim{
+		"https://example.com/claimed": {Link: "https://example.com/claimed", Claimant: "alice"},
+		"https://example.com/done":    {Link: "https://example.com/done", Claimant: "bob", Done: true},
+	}
+
+	got := unclaimedCritical(records, claims, now)
+	if len(got) != 1 || got[0].Link != "https://example.com/unclaimed" || got[0].Title != "Pre-auth RCE" {
+		t.Errorf("unclaimedCritical = %+v, want only the unclaimed recent critical article", got)
+	}
+}
+
+func TestClaimsPerProfile(t *testing.T) {
+	t.Chdir(t.TempDir())
+	useSettings(t, Settings{Profiles: map[string]Profile{
+		"web":    {ChannelID: "-100"},
+		"mobile": {ChannelID: "-200"},
+	}})
+	saved := profileName
+	t.Cleanup(func() { profileName = saved })
+
+	profileName = ""
+	if err := runClaim([]string{"add", "https://example.com/a", "alice"}); err == nil {
+		t.Error("claim add without --profile succeeded with several profiles")
+	}
+
+	profileName = "web"
+	if err := runClaim([]string{"add", "https://example.com/a", "alice"}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		profile string
+		want    int
+	}{
+		{"web", 1},
+		{"mobile", 0},
+	}
+	for _, tt := range tests {
+		claims, err := readClaims(Profile{Name: tt.profile}.stateFile(claimsFileName))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(claims) != tt.want {
+			t.Errorf("profile %s has %d claims, want %d", tt.profile, len(claims), tt.want)
+		}
+	}
+}
+
+func TestRemindOverdueClaimsKeepsFailedReminders(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("TELEGRAM_BOT_TOKEN", "token")
+	t.Setenv("TELEGRAM_CHANNEL_ID", "-100")
+	useSettings(t, Settings{})
+	// Nothing is delivered in a dry run, standing in for a failed send
+	saved := dryRun
+	dryRun = true
+	t.Cleanup(func() { dryRun = saved })
+
+	now := time.Date(2025, 1, 15, 8, 0, 0, 0, time.UTC)
+	filename := Profile{}.stateFile(claimsFileName)
+	claims := map[string]*Claim{
+		"https://example.com/a": {Link: "https://example.com/a", Claimant: "alice", Deadline: now.Add(-time.Hour)},
+	}
+	if err := writeClaims(filename, claims); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := remindOverdueClaims(now); err == nil {
+		t.Error("remindOverdueClaims reported success for an undelivered reminder")
+	}
+	stored, err := readClaims(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stored["https://example.com/a"].LastReminder.IsZero() {
+		t.Error("LastReminder was set although the reminder was not delivered")
+	}
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// Classifier providers: any OpenAI-compatible chat completions API, or a
+// local Ollama server
+const (
+	classifierOpenAI     = "openai"
+	classifierOllama     = "ollama"
+	defaultOpenAIURL     = "https://api.openai.com/v1"
+	defaultOllamaURL     = "http://localhost:11434"
+	classifierTimeout    = 60 * time.Second
+	classifierMaxTextLen = 4000
+	// Items the classifier rejected, so they are not sent to it again
+	classifiedFileName = "classified-url.txt"
+)
+
+// ClassifierSettings enables a language model stage for articles that no
+// keyword matched. Keyword matching stays the only stage when Provider is unset.
+type ClassifierSettings struct {
+	Provider string `yaml:"provider"`
+	URL      string `yaml:"url"`
+	Model    string `yaml:"model"`
+	APIKey   string `yaml:"api_key"`
+	// MinRelevance (0-1) drops classified articles the model is unsure about
+	MinRelevance float64 `yaml:"min_relevance"`
+}
+
+// Classification is the model's verdict on an article
+type Classification struct {
+	Categories []string `json:"categories"`
+	Relevance  float64  `json:"relevance"`
+}
+
+func classifierEnabled() bool {
+	return settings.Classifier.Provider != ""
+}
+
+// classifyArticle asks the model to sort an article no keyword matched into
+// the profile's categories. It returns nil when the model finds none or is not
+// confident enough; exclusions apply as for keyword matches.
+func classifyArticle(ctx context.Context, item *gofeed.Item, extracted *ExtractedContent, keywords map[string]string) (*Article, error) {
+	text := articleText(item, extracted)
+	lowerText := strings.ToLower(text)
+	if _, excluded := excludedBy(text, lowerText, settings.Exclude); excluded {
+		return nil, nil
+	}
+
+	known := make(map[string]struct{})
+	for keyword := range keywords {
+		if tag := tagFor(keyword); tag != "general" {
+			known[tag] = struct{}{}
+		}
+	}
+	categories := make([]string, 0, len(known))
+	for tag := range known {
+		categories = append(categories, tag)
+	}
+	sort.Strings(categories)
+
+	prompt := text
+	if len(prompt) > classifierMaxTextLen {
+		prompt = prompt[:classifierMaxTextLen]
+	}
+	verdict, err := classify(ctx, prompt, categories)
+	if err != nil {
+		return nil, err
+	}
+	if verdict.Relevance < settings.Classifier.MinRelevance {
+		return nil, nil
+	}
+
+	var tags []string
+	for _, category := range verdict.Categories {
+		if _, exists := known[category]; !exists {
+			continue
+		}
+		if _, excluded := excludedBy(text, lowerText, excludedFor(category)); excluded {
+			continue
+		}
+		tags = append(tags, category)
+	}
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	article := newArticle(item, extracted, text, tags, 0)
+	if article != nil {
+		article.Relevance = verdict.Relevance
+	}
+	return article, nil
+}
+
+// classifierPrompt asks for a JSON verdict restricted to the given categories
+func classifierPrompt(text string, categories []string) string {
+	return "You classify security articles. Categories: " + strings.Join(categories, ", ") +
+		".\nReply with JSON only: {\"categories\": [...], \"relevance\": 0.0-1.0}, using only the listed " +
+		"categories and an empty list when none apply. Relevance is how likely the article is a " +
+		"vulnerability writeup worth reading.\n\nArticle:\n" + text
+}
+
+// classify sends the prompt to the configured provider and parses its verdict
+func classify(ctx context.Context, text string, categories []string) (Classification, error) {
+	cfg := settings.Classifier
+	messages := []map[string]string{{"role": "user", "content": classifierPrompt(text, categories)}}
+
+	var endpoint string
+	var payload map[string]any
+	switch cfg.Provider {
+	case classifierOpenAI:
+		endpoint = strings.TrimSuffix(firstNonEmpty(cfg.URL, defaultOpenAIURL), "/") + "/chat/completions"
+		payload = map[string]any{
+			"model":           cfg.Model,
+			"messages":        messages,
+			"temperature":     0,
+			"response_format": map[string]string{"type": "json_object"},
+		}
+	case classifierOllama:
+		endpoint = strings.TrimSuffix(firstNonEmpty(cfg.URL, defaultOllamaURL), "/") + "/api/chat"
+		payload = map[string]any{
+			"model":    cfg.Model,
+			"messages": messages,
+			"format":   "json",
+			"stream":   false,
+		}
+	default:
+		return Classification{}, fmt.Errorf("unknown classifier provider %q", cfg.Provider)
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return Classification{}, fmt.Errorf("encoding classifier request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, classifierTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return Classification{}, fmt.Errorf("creating classifier request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+cfg.APIKey)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return Classification{}, fmt.Errorf("calling classifier: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return Classification{}, &HTTPError{StatusCode: resp.StatusCode, Body: body}
+	}
+
+	var result struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return Classification{}, fmt.Errorf("decoding classifier response: %w", err)
+	}
+
+	content := result.Message.Content
+	if len(result.Choices) > 0 {
+		content = result.Choices[0].Message.Content
+	}
+
+	var verdict Classification
+	if err := json.Unmarshal([]byte(content), &verdict); err != nil {
+		return Classification{}, fmt.Errorf("parsing classifier verdict %q: %w", content, err)
+	}
+	return verdict, nil
+}
@@ -0,0 +1,326 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// settingsFileName holds the optional structured configuration; the feed list
+// and credentials keep living in data.txt and .env
+const settingsFileName = "hunter.yaml"
+
+// Settings is the structured configuration loaded from hunter.yaml
+type Settings struct {
+	// Extraction maps a domain (e.g. "blog.example.com") to CSS selectors
+	// used to pull the body, author and date from article pages
+	Extraction map[string]ExtractionRule `yaml:"extraction"`
+
+	// FullBody fetches every new article page and matches keywords against
+	// its text when no extraction rule covers the domain; feeds can override it
+	FullBody bool `yaml:"full_body"`
+
+	// Readability extracts the main content of every new article page within
+	// the lookback, for matching, the stored summary and the article store's
+	// content archive. Items the feed leaves undated are read for their date.
+	Readability bool `yaml:"readability"`
+
+	// Wayback archives every matched article with Save Page Now
+	Wayback WaybackSettings `yaml:"wayback"`
+
+	// Snapshot saves an HTML or PDF copy of every matched article
+	Snapshot SnapshotSettings `yaml:"snapshot"`
+
+	// PoCMirror snapshots GitHub repos and gists linked from matched articles
+	PoCMirror PoCMirrorSettings `yaml:"poc_mirror"`
+
+	// Profiles are run sequentially unless --profile selects one
+	Profiles map[string]Profile `yaml:"profiles"`
+
+	// Publish writes matched articles as a writeups.xyz-compatible index.json
+	Publish PublishSettings `yaml:"publish"`
+
+	// Categories group synonymous keywords under one tag and thread
+	Categories map[string]Category `yaml:"categories"`
+
+	// Matching sets the default whole-word and case-sensitivity options;
+	// KeywordOptions overrides them for single keywords as written
+	Matching       MatchOptions            `yaml:"matching"`
+	KeywordOptions map[string]MatchOptions `yaml:"keyword_options"`
+
+	// Scoring weighs keywords and only notifies articles above a threshold
+	Scoring ScoringSettings `yaml:"scoring"`
+
+	// Bounty sets a minimum payout for notified writeups
+	Bounty BountySettings `yaml:"bounty"`
+
+	// Programs names bug bounty programs and vendors to tag, by name and alias
+	Programs map[string]Program `yaml:"programs"`
+
+	// Taxonomy names a file mapping canonical tags to aliases (default
+	// taxonomy.yaml); each tag becomes a category of its aliases
+	Taxonomy string `yaml:"taxonomy"`
+
+	// Languages allowlists article languages for profiles that set none
+	Languages []string `yaml:"languages"`
+
+	// Telegram tunes how notifications are sent
+	Telegram TelegramSettings `yaml:"telegram"`
+
+	// Digest batches matches into one message per category
+	Digest DigestSettings `yaml:"digest"`
+
+	// Routes map keywords and categories to a chat and optional topic,
+	// taking precedence over destinations
+	Routes map[string]Route `yaml:"routes"`
+
+	// Failover receives notifications when Telegram is down for a whole run
+	Failover FailoverSettings `yaml:"failover"`
+
+	// QuietHours silences or holds notifications overnight
+	QuietHours QuietHoursSettings `yaml:"quiet_hours"`
+
+	// Authors lists researchers to always or never notify, by name
+	Authors map[string]AuthorRule `yaml:"authors"`
+
+	// Classifier asks a language model about articles no keyword matched
+	Classifier ClassifierSettings `yaml:"classifier"`
+
+	// Severity labels articles critical/high/medium/info, filters and routes on it
+	Severity SeveritySettings `yaml:"severity"`
+
+	// Embeddings match articles by similarity to example writeups
+	Embeddings EmbeddingSettings `yaml:"embeddings"`
+
+	// Exclude drops any article containing one of these terms (plain or
+	// /regex/), e.g. giveaways and job posts; categories add their own
+	Exclude []string `yaml:"exclude"`
+
+	// Destinations are the chats notified by profiles that define none of their own
+	Destinations []Destination `yaml:"destinations"`
+
+	// Lookback is the default age limit for notified articles ("7d", "36h" or
+	// "last-run"); feeds can override it
+	Lookback string `yaml:"lookback"`
+
+	// FeedTimeout bounds fetching one feed including retries; RunTimeout bounds
+	// the whole run. Both accept Go durations such as "90s" or "1h".
+	FeedTimeout time.Duration `yaml:"feed_timeout"`
+	RunTimeout  time.Duration `yaml:"run_timeout"`
+
+	// FeedBudget is a hard wall-clock limit per feed that abandons fetching
+	// and parsing even when a slow-drip server or giant XML ignores the
+	// timeout; it defaults to feed_timeout plus 30s
+	FeedBudget time.Duration `yaml:"feed_budget"`
+
+	// FeedStagger is the pause between feeds, 5s by default
+	FeedStagger time.Duration `yaml:"feed_stagger"`
+
+	// Schedule is when "serve" runs profiles without their own schedule
+	Schedule string `yaml:"schedule"`
+
+	// SlowFeeds posts the slowest feeds of each run
+	SlowFeeds SlowFeedSettings `yaml:"slow_feeds"`
+
+	// Pprof exposes runtime profiles in serve mode
+	Pprof PprofSettings `yaml:"pprof"`
+
+	// Heartbeat pings a monitoring URL after each successful run
+	Heartbeat HeartbeatSettings `yaml:"heartbeat"`
+
+	// Listen is the address "serve" exposes /metrics, /healthz and /readyz
+	// on, e.g. ":9090"
+	Listen string `yaml:"listen"`
+
+	// HealthStaleAfter is how long runs may go without fetching a feed
+	// before /healthz fails; by default it follows the schedules
+	HealthStaleAfter time.Duration `yaml:"health_stale_after"`
+
+	// HTTPCache reuses feed responses on disk while Cache-Control/Expires allow
+	HTTPCache HTTPCacheSettings `yaml:"http_cache"`
+
+	// UserAgent replaces the default User-Agent for fetches; UserAgents, when
+	// set, are rotated round-robin instead
+	UserAgent  string   `yaml:"user_agent"`
+	UserAgents []string `yaml:"user_agents"`
+
+	// HTTP tunes timeouts, connection pooling, TLS and redirects of the shared client
+	HTTP HTTPSettings `yaml:"http"`
+
+	// MaxResponseMB caps the decoded size of any fetched response (default 20)
+	MaxResponseMB int `yaml:"max_response_mb"`
+
+	// Proxy routes feed fetches and Telegram calls through an http(s):// or
+	// socks5:// proxy; when unset HTTP_PROXY/HTTPS_PROXY are honored
+	Proxy string `yaml:"proxy"`
+
+	// Tor sends fetches through a Tor SOCKS port, optionally one circuit per domain
+	Tor TorSettings `yaml:"tor"`
+
+	// BrowserPath points at the Chrome/Chromium binary for `render: browser`
+	// feeds; by default the usual install locations are searched
+	BrowserPath string `yaml:"browser_path"`
+
+	// CircuitBreaker skips a domain's remaining feeds after repeated failures
+	CircuitBreaker CircuitBreakerSettings `yaml:"circuit_breaker"`
+
+	// FeedHealth quarantines feeds that keep failing across runs
+	FeedHealth FeedHealthSettings `yaml:"feed_health"`
+
+	// Twitter reads x: sources from the X API or a Nitter instance
+	Twitter TwitterSettings `yaml:"twitter"`
+
+	// GitHub authenticates github: sources against the API rate limit
+	GitHub GitHubSettings `yaml:"github"`
+
+	// NVD holds the API key for nvd: sources
+	NVD NVDSettings `yaml:"nvd"`
+
+	// RSSHub is the instance rsshub: routes are read from
+	RSSHub RSSHubSettings `yaml:"rsshub"`
+
+	// IMAP is the mailbox imap: newsletter folders are read from
+	IMAP IMAPSettings `yaml:"imap"`
+
+	// Plugins are external commands serving plugin: sources
+	Plugins map[string]PluginSettings `yaml:"plugins"`
+
+	// Dedup selects how already-seen articles are recognized: url (default),
+	// canonical-url, guid, fuzzy-title or content-hash
+	Dedup string `yaml:"dedup"`
+
+	// Feeds holds per-feed options keyed by the feed URL as listed in data.txt
+	Feeds map[string]FeedOptions `yaml:"feeds"`
+}
+
+// FeedOptions overrides how a single feed is fetched and processed
+type FeedOptions struct {
+	// Type selects special handling; "advisory" diffs the feed against stored
+	// state and reports only additions and severity changes, "sitemap" polls
+	// a sitemap.xml and reads new pages for their title and description,
+	// "json" reads any JSON API through the JSON field mapping
+	Type string `yaml:"type"`
+
+	// JSON maps the fields of a "json" type feed, see JSONMapping
+	JSON JSONMapping `yaml:"json"`
+
+	// FullBody overrides the global full_body setting for this feed
+	FullBody *bool `yaml:"full_body"`
+
+	// Limit caps how many entries a source yields per run (sitemaps: 20)
+	Limit int `yaml:"limit"`
+
+	// Render: browser loads the feed URL in headless Chrome and reads the
+	// article links from the rendered page, for client-side rendered blogs
+	Render string `yaml:"render"`
+
+	// Dedup overrides the global dedup strategy for this feed
+	Dedup string `yaml:"dedup"`
+
+	// Lookback overrides the run's lookback window for this feed
+	Lookback string `yaml:"lookback"`
+
+	// Timeout overrides the feed timeout for slow but valuable feeds
+	Timeout time.Duration `yaml:"timeout"`
+
+	// UserAgent pins the User-Agent for feeds that only accept certain agents
+	UserAgent string `yaml:"user_agent"`
+
+	// Proxy overrides the global proxy for this feed
+	Proxy string `yaml:"proxy"`
+
+	// Headers, BasicAuth and BearerToken authenticate private feeds; use
+	// ${VAR} interpolation to keep the credentials out of the file
+	Headers     map[string]string `yaml:"headers"`
+	BasicAuth   *BasicAuth        `yaml:"basic_auth"`
+	BearerToken string            `yaml:"bearer_token"`
+}
+
+// BasicAuth holds HTTP basic credentials for a feed
+type BasicAuth struct {
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// applyFeedAuth adds a feed's configured headers and credentials to a request
+func applyFeedAuth(req *http.Request, feedURL string) {
+	options := feedOptions(feedURL)
+	for name, value := range options.Headers {
+		req.Header.Set(name, value)
+	}
+	if options.BasicAuth != nil {
+		req.SetBasicAuth(options.BasicAuth.Username, options.BasicAuth.Password)
+	}
+	if options.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+options.BearerToken)
+	} else if isTwitterAPI(feedURL) && settings.Twitter.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+settings.Twitter.BearerToken)
+	} else if strings.HasPrefix(feedURL, githubAPIBase) && settings.GitHub.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+settings.GitHub.Token)
+	}
+}
+
+// feedOptions returns the options configured for a feed URL, if any
+func feedOptions(feedURL string) FeedOptions {
+	return settings.Feeds[feedURL]
+}
+
+// fullBodyFor reports whether a feed's article pages are read for matching
+func fullBodyFor(feedURL string) bool {
+	if fullBody := feedOptions(feedURL).FullBody; fullBody != nil {
+		return *fullBody
+	}
+	return settings.FullBody
+}
+
+// PoCMirrorSettings enables PoC mirroring when Dir is set; Push optionally
+// names a private git remote that receives a copy of every mirror. GitHub
+// repositories over MaxSizeMB (default 100, negative for no limit) are skipped.
+type PoCMirrorSettings struct {
+	Dir       string `yaml:"dir"`
+	Push      string `yaml:"push"`
+	MaxSizeMB int    `yaml:"max_size_mb"`
+}
+
+// ExtractionRule holds CSS selectors for one domain; empty selectors are skipped
+type ExtractionRule struct {
+	Body   string `yaml:"body"`
+	Author string `yaml:"author"`
+	Date   string `yaml:"date"`
+}
+
+var settings Settings
+
+// loadSettings reads hunter.yaml; a missing file yields empty settings
+func loadSettings(filename string) (Settings, error) {
+	var s Settings
+
+	data, err := os.ReadFile(filename)
+	if err != nil && !os.IsNotExist(err) {
+		return s, fmt.Errorf("reading %s: %w", filename, err)
+	}
+
+	// ${VAR} references in string values are resolved after parsing so
+	// secrets can stay in the environment
+	if err == nil {
+		var document yaml.Node
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return s, fmt.Errorf("parsing %s: %w", filename, err)
+		}
+		interpolateNode(&document)
+		if err := document.Decode(&s); err != nil {
+			return s, fmt.Errorf("parsing %s: %w", filename, err)
+		}
+	}
+
+	taxonomy, err := readTaxonomy(firstNonEmpty(s.Taxonomy, defaultTaxonomyFileName))
+	if err != nil {
+		return s, err
+	}
+	mergeTaxonomy(&s, taxonomy)
+	return s, nil
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// cvePattern matches CVE identifiers such as CVE-2024-12345
+var cvePattern = regexp.MustCompile(`(?i)\bCVE-\d{4}-\d{4,}\b`)
+
+// extractCVEs returns the distinct CVE IDs in the text, uppercased, in order
+// of first mention
+func extractCVEs(text string) []string {
+	var cves []string
+	seen := make(map[string]struct{})
+	for _, match := range cvePattern.FindAllString(text, -1) {
+		id := strings.ToUpper(match)
+		if _, exists := seen[id]; exists {
+			continue
+		}
+		seen[id] = struct{}{}
+		cves = append(cves, id)
+	}
+	return cves
+}
+
+// runCVE lists stored articles of the profiles selected with --profile that
+// mention a CVE:
+//
+//	cve CVE-2024-12345
+func runCVE(args []string) error {
+	if len(args) != 1 || !cvePattern.MatchString(args[0]) {
+		return fmt.Errorf("usage: cve <CVE-YYYY-NNNN>")
+	}
+	id := strings.ToUpper(args[0])
+
+	profiles, err := commandProfiles()
+	if err != nil {
+		return err
+	}
+
+	found := 0
+	for _, profile := range profiles {
+		records, err := readStoredArticles(profile.stateFile(articleStoreFileName))
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			for _, cve := range record.CVEs {
+				if cve == id {
+					fmt.Printf("%s  %s%s\n  %s\n", record.IngestedAt.Format("2006-01-02"), profile.label(), record.Title, record.Link)
+					found++
+					break
+				}
+			}
+		}
+	}
+	if found == 0 {
+		fmt.Printf("No stored articles mention %s\n", id)
+	}
+	return nil
+}
+
+// Enrichment looks up at most this many CVEs per article to stay within NVD's
+// rate limit, and lists at most maxCVEProducts affected products
+const (
+	maxEnrichedCVEs = 3
+	maxCVEProducts  = 3
+)
+
+// CVEDetail is NVD's summary of a CVE mentioned in an article
+type CVEDetail struct {
+	ID       string   `json:"id"`
+	Score    float64  `json:"cvss,omitempty"`
+	Severity string   `json:"severity,omitempty"`
+	CWEs     []string `json:"cwes,omitempty"`
+	Products []string `json:"products,omitempty"`
+}
+
+// String renders the detail as one notification line
+func (d CVEDetail) String() string {
+	parts := []string{d.ID}
+	if d.Score > 0 {
+		parts = append(parts, strings.TrimSpace(fmt.Sprintf("CVSS %.1f %s", d.Score, d.Severity)))
+	}
+	parts = append(parts, d.CWEs...)
+	products := d.Products
+	if len(products) > maxCVEProducts {
+		products = append(products[:maxCVEProducts:maxCVEProducts], "…")
+	}
+	if len(products) > 0 {
+		parts = append(parts, strings.Join(products, ", "))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// cveDetails caches lookups for the rest of the run
+var cveDetails = map[string]*CVEDetail{}
+
+// enrichCVEs looks up the first CVEs of an article in NVD
+func enrichCVEs(ctx context.Context, cves []string) []CVEDetail {
+	var details []CVEDetail
+	for i, id := range cves {
+		if i == maxEnrichedCVEs {
+			break
+		}
+		detail, cached := cveDetails[id]
+		if !cached {
+			var err error
+			if detail, err = lookupCVE(ctx, id); err != nil {
+				printError(fmt.Sprintf("Error looking up %s: %v", id, err))
+				continue
+			}
+			cveDetails[id] = detail
+		}
+		if detail != nil {
+			details = append(details, *detail)
+		}
+	}
+	return details
+}
+
+// lookupCVE fetches one CVE from the NVD API; unknown IDs return nil
+func lookupCVE(ctx context.Context, id string) (*CVEDetail, error) {
+	ctx, cancel := context.WithTimeout(ctx, extractionTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, nvdAPIURL+"?cveId="+url.QueryEscape(id), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	setUserAgent(req)
+	if settings.NVD.APIKey != "" {
+		req.Header.Set("apiKey", settings.NVD.APIKey)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching NVD: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &HTTPError{StatusCode: resp.StatusCode}
+	}
+
+	var response nvdResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("decoding NVD response: %w", err)
+	}
+	if len(response.Vulnerabilities) == 0 {
+		return nil, nil
+	}
+
+	cve := response.Vulnerabilities[0].CVE
+	score, severity := cve.cvss()
+	return &CVEDetail{
+		ID:       cve.ID,
+		Score:    score,
+		Severity: severity,
+		CWEs:     cve.cwes(),
+		Products: cve.products(),
+	}, nil
+}
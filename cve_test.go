@@ -0,0 +1,27 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestExtractCVEs(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", nil},
+		{"No identifiers here", nil},
+		{"Exploiting CVE-2024-12345", []string{"CVE-2024-12345"}},
+		{"cve-2021-44228 and CVE-2021-44228 again", []string{"CVE-2021-44228"}},
+		{"CVE-2023-1234, then (CVE-2022-22965).", []string{"CVE-2023-1234", "CVE-2022-22965"}},
+		// Fewer than four digits is not a CVE ID
+		{"CVE-2024-123", nil},
+		{"XCVE-2024-12345", nil},
+	}
+	for _, tt := range tests {
+		if got := extractCVEs(tt.text); !slices.Equal(got, tt.want) {
+			t.Errorf("extractCVEs(%q) = %v, want %v", tt.text, got, tt.want)
+		}
+	}
+}
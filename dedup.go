@@ -0,0 +1,138 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// DedupStrategy derives the key under which an item is remembered in
+// found-url.txt; two items with the same key are treated as duplicates
+type DedupStrategy interface {
+	Name() string
+	Key(item *gofeed.Item) string
+}
+
+const defaultDedupStrategy = "url"
+
+// dedupStrategies lists the selectable strategies by config name
+var dedupStrategies = map[string]DedupStrategy{
+	"url":           exactURLDedup{},
+	"canonical-url": canonicalURLDedup{},
+	"guid":          guidDedup{},
+	"fuzzy-title":   fuzzyTitleDedup{},
+	"content-hash":  contentHashDedup{},
+}
+
+// dedupStrategyFor picks the feed's strategy, then the global one, then exact URL
+func dedupStrategyFor(feedURL string) (DedupStrategy, error) {
+	name := feedOptions(feedURL).Dedup
+	if name == "" {
+		name = settings.Dedup
+	}
+	if name == "" {
+		name = defaultDedupStrategy
+	}
+	strategy, exists := dedupStrategies[name]
+	if !exists {
+		return nil, fmt.Errorf("unknown dedup strategy %q", name)
+	}
+	return strategy, nil
+}
+
+// isDuplicate checks the strategy key and, for history written before the
+// strategy was changed, the raw link
+func isDuplicate(strategy DedupStrategy, item *gofeed.Item, seen map[string]struct{}) bool {
+	if _, exists := seen[strategy.Key(item)]; exists {
+		return true
+	}
+	_, exists := seen[item.Link]
+	return exists
+}
+
+// exactURLDedup is the historical behavior: the link as published
+type exactURLDedup struct{}
+
+func (exactURLDedup) Name() string                 { return "url" }
+func (exactURLDedup) Key(item *gofeed.Item) string { return item.Link }
+
+// canonicalURLDedup ignores tracking parameters, fragments, scheme, host case
+// and trailing slashes
+type canonicalURLDedup struct{}
+
+func (canonicalURLDedup) Name() string { return "canonical-url" }
+
+func (canonicalURLDedup) Key(item *gofeed.Item) string {
+	return canonicalURL(item.Link)
+}
+
+func canonicalURL(rawURL string) string {
+	parsed, err := url.Parse(cleanURL(rawURL))
+	if err != nil {
+		return rawURL
+	}
+	parsed.Scheme = "https"
+	parsed.Host = strings.TrimPrefix(strings.ToLower(parsed.Host), "www.")
+	parsed.Fragment = ""
+	parsed.Path = strings.TrimSuffix(parsed.Path, "/")
+	return parsed.String()
+}
+
+// guidDedup uses the feed-provided GUID, falling back to the link
+type guidDedup struct{}
+
+func (guidDedup) Name() string { return "guid" }
+
+func (guidDedup) Key(item *gofeed.Item) string {
+	if item.GUID == "" {
+		return item.Link
+	}
+	return "guid:" + item.GUID
+}
+
+// fuzzyTitleDedup treats titles with the same significant words as duplicates,
+// regardless of case, punctuation, word order and filler words, which catches
+// the same writeup cross-posted to several platforms
+type fuzzyTitleDedup struct{}
+
+var titleStopwords = map[string]struct{}{
+	"a": {}, "an": {}, "the": {}, "of": {}, "in": {}, "on": {}, "to": {}, "for": {},
+	"and": {}, "or": {}, "my": {}, "how": {}, "i": {}, "with": {}, "via": {},
+}
+
+func (fuzzyTitleDedup) Name() string { return "fuzzy-title" }
+
+func (fuzzyTitleDedup) Key(item *gofeed.Item) string {
+	words := strings.FieldsFunc(strings.ToLower(item.Title), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	var significant []string
+	for _, word := range words {
+		if _, stop := titleStopwords[word]; !stop {
+			significant = append(significant, word)
+		}
+	}
+	if len(significant) == 0 {
+		return item.Link
+	}
+	sort.Strings(significant)
+	return "title:" + strings.Join(significant, " ")
+}
+
+// contentHashDedup hashes the whitespace-normalized title and body
+type contentHashDedup struct{}
+
+func (contentHashDedup) Name() string { return "content-hash" }
+
+func (contentHashDedup) Key(item *gofeed.Item) string {
+	text := strings.Join(strings.Fields(item.Title+" "+item.Description+" "+item.Content), " ")
+	sum := sha256.Sum256([]byte(text))
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
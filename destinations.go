@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Destination is one Telegram chat that receives a profile's notifications,
+// with optional thread overrides and an optional keyword filter
+type Destination struct {
+	Name      string            `yaml:"name"`
+	ChannelID string            `yaml:"channel_id"`
+	Threads   map[string]string `yaml:"threads"`
+	Keywords  []string          `yaml:"keywords"`
+}
+
+// accepts reports whether the destination wants notifications for a tag;
+// run status messages ("general") and maintenance reports always go through
+func (d Destination) accepts(tag string) bool {
+	if len(d.Keywords) == 0 || tag == "general" || tag == slowFeedsTag {
+		return true
+	}
+	for _, keyword := range d.Keywords {
+		if strings.EqualFold(keyword, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// threadFor prefers the destination's own thread mapping over the profile's,
+// then a forum topic created for the tag. With telegram.auto_topics, a
+// category without any thread gets a new topic instead of the general one.
+func (d Destination) threadFor(ctx context.Context, p Profile, botToken, tag string) string {
+	if thread, exists := d.Threads[tag]; exists {
+		return thread
+	}
+	if thread, exists := p.configuredThread(tag); exists {
+		return thread
+	}
+	if thread, exists := storedTopic(d.ChannelID, tag); exists {
+		return thread
+	}
+	if _, isCategory := settings.Categories[tag]; isCategory && settings.Telegram.AutoTopics && !dryRun {
+		thread, err := ensureTopic(ctx, botToken, d.ChannelID, tag)
+		if err == nil {
+			return thread
+		}
+		printError(fmt.Sprintf("Creating forum topic for %s: %v", tag, err))
+	}
+	return keywords["general"]
+}
+
+// notify sends a plain message tagged with a keyword, category or "general"
+// to every destination of the profile that accepts it
+func (p Profile) notify(ctx context.Context, botToken, tag, message string) {
+	p.send(ctx, botToken, tag, Notification{Text: message})
+}
+
+// send delivers a notification to the tag's route when the routing table has
+// one, otherwise to every destination that accepts the tag, and returns the
+// messages that went out
+func (p Profile) send(ctx context.Context, botToken, tag string, notification Notification) []SentMessage {
+	if notification.Article != nil {
+		notification.Store = p.stateFile(articleStoreFileName)
+	}
+	var sent []SentMessage
+	if route, exists := routeFor(tag); exists {
+		if message := sendToTelegram(ctx, notification, botToken, route.ChatID, route.Thread); message != nil {
+			sent = append(sent, *message)
+		}
+		return sent
+	}
+	for _, destination := range p.Destinations {
+		if !destination.accepts(tag) {
+			continue
+		}
+		message := sendToTelegram(ctx, notification, botToken, destination.ChannelID, destination.threadFor(ctx, p, botToken, tag))
+		if message != nil {
+			sent = append(sent, *message)
+		}
+	}
+	return sent
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+const (
+	digestFileName  = "digest.json"
+	digestModeRun   = "run"
+	digestModeDaily = "daily"
+	// Telegram rejects messages longer than this
+	telegramMessageLimit = 4096
+)
+
+// DigestSettings batches matches into one message per category instead of
+// one per article: at the end of every run, or once a day from Hour (local
+// time) with the matches of all runs since the last digest
+type DigestSettings struct {
+	Mode string `yaml:"mode"`
+	Hour int    `yaml:"hour"`
+}
+
+// DigestEntry is one article waiting for the digest of a tag
+type DigestEntry struct {
+	Tag   string `json:"tag"`
+	Title string `json:"title"`
+	Link  string `json:"link"`
+}
+
+// DigestState carries daily digest entries between runs
+type DigestState struct {
+	Entries  []DigestEntry `json:"entries"`
+	LastSent time.Time     `json:"last_sent"`
+}
+
+func digestEnabled() bool {
+	return settings.Digest.Mode != ""
+}
+
+func readDigestState(filename string) (DigestState, error) {
+	var state DigestState
+
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("reading %s: %w", filename, err)
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+	return state, nil
+}
+
+func writeDigestState(filename string, state DigestState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding digest: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", filename, err)
+	}
+	return nil
+}
+
+// digestDue reports whether the pending entries go out now: always for run
+// digests, once per day from the configured hour for daily ones
+func digestDue(state DigestState, now time.Time) bool {
+	if settings.Digest.Mode != digestModeDaily {
+		return true
+	}
+	if now.Hour() < settings.Digest.Hour {
+		return false
+	}
+	y1, m1, d1 := state.LastSent.In(now.Location()).Date()
+	y2, m2, d2 := now.Date()
+	return y1 != y2 || m1 != m2 || d1 != d2
+}
+
+// sendDigest posts one message per tag (split at Telegram's length limit)
+// and clears the entries. The daily digest ends with the recent critical
+// articles nobody has claimed.
+func sendDigest(ctx context.Context, p Profile, botToken string, state *DigestState, now time.Time) {
+	if !digestDue(*state, now) {
+		return
+	}
+	var unclaimed []DigestEntry
+	if settings.Digest.Mode == digestModeDaily {
+		unclaimed = profileUnclaimedCritical(p, now)
+	}
+	if len(state.Entries) == 0 && len(unclaimed) == 0 {
+		return
+	}
+
+	byTag := make(map[string][]DigestEntry)
+	var tags []string
+	for _, entry := range state.Entries {
+		if _, exists := byTag[entry.Tag]; !exists {
+			tags = append(tags, entry.Tag)
+		}
+		byTag[entry.Tag] = append(byTag[entry.Tag], entry)
+	}
+	sort.Strings(tags)
+
+	f := messageFormatter{mode: settings.Telegram.ParseMode}
+	var delivered []string
+	for _, tag := range tags {
+		title := fmt.Sprintf("📰 %s digest (%d)", tag, len(byTag[tag]))
+		sent := false
+		for _, message := range formatDigest(f, title, byTag[tag]) {
+			if len(p.send(ctx, botToken, tag, Notification{Text: message, ParseMode: f.mode})) > 0 {
+				sent = true
+			}
+			printSuccess(message)
+		}
+		if sent {
+			for _, entry := range byTag[tag] {
+				delivered = append(delivered, entry.Link)
+			}
+		}
+	}
+	if len(delivered) > 0 {
+		recordDelivered(p.stateFile(articleStoreFileName), delivered...)
+	}
+	if len(unclaimed) > 0 {
+		title := fmt.Sprintf("🚨 Unclaimed critical (%d)", len(unclaimed))
+		for _, message := range formatDigest(f, title, unclaimed) {
+			p.send(ctx, botToken, "general", Notification{Text: message, ParseMode: f.mode})
+			printSuccess(message)
+		}
+	}
+	printStatus(fmt.Sprintf("Sent digest of %d articles in %d categories", len(state.Entries), len(tags)), color.FgGreen)
+
+	state.Entries = nil
+	state.LastSent = now
+}
+
+// formatDigest renders entries as a titled list, starting a new message
+// whenever the next line would pass the length limit
+func formatDigest(f messageFormatter, title string, entries []DigestEntry) []string {
+	header := f.bold(title) + "\n"
+
+	var messages []string
+	current := header
+	for _, entry := range entries {
+		var line string
+		if f.mode == "" {
+			line = fmt.Sprintf("• %s\n  %s\n", entry.Title, cleanURL(entry.Link))
+		} else {
+			line = "• " + f.link(entry.Title, cleanURL(entry.Link)) + "\n"
+		}
+		if len(current)+len(line) > telegramMessageLimit-len(environmentPrefix()) && current != header {
+			messages = append(messages, strings.TrimSuffix(current, "\n"))
+			current = header
+		}
+		current += line
+	}
+	return append(messages, strings.TrimSuffix(current, "\n"))
+}
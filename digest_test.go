@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormatDigest(t *testing.T) {
+	useSettings(t, Settings{})
+
+	entries := []DigestEntry{
+		{Tag: "xss", Title: "One", Link: "https://example.com/1?utm_source=feed"},
+		{Tag: "xss", Title: "Two", Link: "https://example.com/2"},
+	}
+
+	tests := []struct {
+		name string
+		mode string
+		want string
+	}{
+		{"plain", "", "📰 xss\n• One\n  https://example.com/1\n• Two\n  https://example.com/2"},
+		{"HTML", parseModeHTML, "<b>📰 xss</b>\n• <a href=\"https://example.com/1\">One</a>\n• <a href=\"https://example.com/2\">Two</a>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatDigest(messageFormatter{mode: tt.mode}, "📰 xss", entries)
+			if len(got) != 1 || got[0] != tt.want {
+				t.Errorf("formatDigest = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatDigestSplits(t *testing.T) {
+	useSettings(t, Settings{})
+
+	var entries []DigestEntry
+	for i := range 200 {
+		entries = append(entries, DigestEntry{
+			Title: fmt.Sprintf("Article %d %s", i, strings.Repeat("x", 40)),
+			Link:  fmt.Sprintf("https://example.com/%d", i),
+		})
+	}
+
+	messages := formatDigest(messageFormatter{}, "digest", entries)
+	if len(messages) < 2 {
+		t.Fatalf("got %d messages, want the digest split", len(messages))
+	}
+	lines := 0
+	for _, message := range messages {
+		if len(message) > telegramMessageLimit {
+			t.Errorf("message of %d bytes passes the limit", len(message))
+		}
+		if !strings.HasPrefix(message, "digest\n") {
+			t.Errorf("message does not start with the title: %q", message[:20])
+		}
+		lines += strings.Count(message, "• ")
+	}
+	if lines != len(entries) {
+		t.Errorf("messages list %d entries, want %d", lines, len(entries))
+	}
+}
+
+func TestDigestDue(t *testing.T) {
+	now := time.Date(2025, 1, 15, 9, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		digest   DigestSettings
+		lastSent time.Time
+		want     bool
+	}{
+		{"run mode", DigestSettings{Mode: digestModeRun}, now, true},
+		{"daily before the hour", DigestSettings{Mode: digestModeDaily, Hour: 10}, time.Time{}, false},
+		{"daily never sent", DigestSettings{Mode: digestModeDaily, Hour: 9}, time.Time{}, true},
+		{"daily sent yesterday", DigestSettings{Mode: digestModeDaily, Hour: 9}, now.AddDate(0, 0, -1), true},
+		{"daily sent today", DigestSettings{Mode: digestModeDaily, Hour: 9}, now.Add(-10 * time.Minute), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useSettings(t, Settings{Digest: tt.digest})
+			if got := digestDue(DigestState{LastSent: tt.lastSent}, now); got != tt.want {
+				t.Errorf("digestDue = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
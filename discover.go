@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+const discoveredFeedsFileName = "discovered-feeds.json"
+
+// discoveredFeeds maps blog pages listed in data.txt to the feed found on them,
+// so later runs go straight to the feed
+var discoveredFeeds = map[string]string{}
+
+// feedLinkTypes are the <link rel="alternate"> types accepted as feeds, in
+// order of preference
+var feedLinkTypes = []string{
+	"application/rss+xml",
+	"application/atom+xml",
+	"application/feed+json",
+	"application/json",
+}
+
+// discoverFeedURL looks for <link rel="alternate"> feed references in an HTML page
+func discoverFeedURL(body []byte, pageURL string) (string, bool) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return "", false
+	}
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return "", false
+	}
+
+	candidates := make(map[string]string)
+	doc.Find(`link[rel~="alternate"][href]`).Each(func(_ int, sel *goquery.Selection) {
+		linkType := strings.ToLower(strings.TrimSpace(sel.AttrOr("type", "")))
+		// Comment feeds are alternates too but rarely what the user wants
+		if strings.Contains(strings.ToLower(sel.AttrOr("title", "")), "comments") {
+			return
+		}
+		if _, exists := candidates[linkType]; !exists {
+			candidates[linkType] = sel.AttrOr("href", "")
+		}
+	})
+
+	for _, linkType := range feedLinkTypes {
+		href, exists := candidates[linkType]
+		if !exists {
+			continue
+		}
+		resolved, err := base.Parse(href)
+		if err != nil {
+			continue
+		}
+		return resolved.String(), true
+	}
+	return "", false
+}
+
+func readDiscoveredFeeds(filename string) (map[string]string, error) {
+	feeds := make(map[string]string)
+
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return feeds, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", filename, err)
+	}
+	if err := json.Unmarshal(data, &feeds); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+	return feeds, nil
+}
+
+func writeDiscoveredFeeds(filename string, feeds map[string]string) error {
+	data, err := json.MarshalIndent(feeds, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding discovered feeds: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", filename, err)
+	}
+	return nil
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+const defaultDNSCacheTTL = 5 * time.Minute
+
+// DNSSettings replaces the system resolver for outbound connections. Server
+// is a plain DNS server ("1.1.1.1:53"); DoH is a DNS-over-HTTPS endpoint
+// speaking the JSON API (Cloudflare, Google). Answers are cached for CacheTTL.
+type DNSSettings struct {
+	Server   string        `yaml:"server"`
+	DoH      string        `yaml:"doh"`
+	CacheTTL time.Duration `yaml:"cache_ttl"`
+}
+
+// dnsCacheEntry holds the addresses of one host until expires
+type dnsCacheEntry struct {
+	addrs   []string
+	expires time.Time
+}
+
+// cachingResolver resolves hosts through the configured lookup and keeps the
+// answers in memory, since a run connects to the same few hundred hosts
+type cachingResolver struct {
+	lookup func(ctx context.Context, host string) ([]string, error)
+	ttl    time.Duration
+	dialer *net.Dialer
+
+	mu    sync.Mutex
+	cache map[string]dnsCacheEntry
+}
+
+// newCachingResolver picks DoH, a custom server or the system resolver
+func newCachingResolver(cfg DNSSettings, dialer *net.Dialer) (*cachingResolver, error) {
+	r := &cachingResolver{
+		ttl:    cfg.CacheTTL,
+		dialer: dialer,
+		cache:  make(map[string]dnsCacheEntry),
+	}
+	if r.ttl <= 0 {
+		r.ttl = defaultDNSCacheTTL
+	}
+
+	switch {
+	case cfg.DoH != "" && cfg.Server != "":
+		return nil, fmt.Errorf("http.dns: set either server or doh, not both")
+	case cfg.DoH != "":
+		endpoint, err := url.Parse(cfg.DoH)
+		if err != nil || endpoint.Scheme != "https" {
+			return nil, fmt.Errorf("http.dns.doh: invalid endpoint %q", cfg.DoH)
+		}
+		// The DoH endpoint itself is resolved by the system resolver
+		client := &http.Client{Timeout: 10 * time.Second}
+		r.lookup = func(ctx context.Context, host string) ([]string, error) {
+			return lookupDoH(ctx, client, endpoint, host)
+		}
+	case cfg.Server != "":
+		server := cfg.Server
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, "53")
+		}
+		resolver := &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, server)
+			},
+		}
+		r.lookup = resolver.LookupHost
+	default:
+		r.lookup = net.DefaultResolver.LookupHost
+	}
+	return r, nil
+}
+
+// resolve returns the cached addresses of host, looking them up when stale
+func (r *cachingResolver) resolve(ctx context.Context, host string) ([]string, error) {
+	if net.ParseIP(host) != nil {
+		return []string{host}, nil
+	}
+
+	r.mu.Lock()
+	entry, exists := r.cache[host]
+	r.mu.Unlock()
+	if exists && time.Now().Before(entry.expires) {
+		return entry.addrs, nil
+	}
+
+	addrs, err := r.lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no addresses for %s", host)
+	}
+
+	r.mu.Lock()
+	r.cache[host] = dnsCacheEntry{addrs: addrs, expires: time.Now().Add(r.ttl)}
+	r.mu.Unlock()
+	return addrs, nil
+}
+
+// DialContext resolves through the cache and tries each address in turn
+func (r *cachingResolver) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := r.resolve(ctx, host)
+	if err != nil {
+		return nil, fmt.Errorf("resolving %s: %w", host, err)
+	}
+
+	var lastErr error
+	for _, addr := range addrs {
+		conn, err := r.dialer.DialContext(ctx, network, net.JoinHostPort(addr, port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// dohResponse is the JSON API answer format shared by Cloudflare and Google
+type dohResponse struct {
+	Status int `json:"Status"`
+	Answer []struct {
+		Type int    `json:"type"`
+		Data string `json:"data"`
+	} `json:"Answer"`
+}
+
+const (
+	dnsTypeA    = 1
+	dnsTypeAAAA = 28
+)
+
+// lookupDoH queries A and AAAA records over DNS-over-HTTPS
+func lookupDoH(ctx context.Context, client *http.Client, endpoint *url.URL, host string) ([]string, error) {
+	var addrs []string
+	for _, recordType := range []string{"A", "AAAA"} {
+		query := *endpoint
+		params := query.Query()
+		params.Set("name", host)
+		params.Set("type", recordType)
+		query.RawQuery = params.Encode()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, query.String(), nil)
+		if err != nil {
+			return nil, fmt.Errorf("creating DoH request: %w", err)
+		}
+		req.Header.Set("Accept", "application/dns-json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, fmt.Errorf("querying DoH: %w", err)
+		}
+		var answer dohResponse
+		err = json.NewDecoder(resp.Body).Decode(&answer)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("decoding DoH answer: %w", err)
+		}
+		if answer.Status != 0 {
+			return nil, fmt.Errorf("DoH lookup of %s failed with rcode %d", host, answer.Status)
+		}
+
+		for _, record := range answer.Answer {
+			if record.Type == dnsTypeA || record.Type == dnsTypeAAAA {
+				addrs = append(addrs, record.Data)
+			}
+		}
+	}
+	return addrs, nil
+}
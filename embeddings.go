@@ -0,0 +1,289 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fatih/color"
+	"github.com/mmcdole/gofeed"
+)
+
+const (
+	embeddingsFileName      = "embeddings.json"
+	defaultEmbeddingMinimum = 0.8
+	// Items that resembled no example, so they are not embedded again
+	embeddedFileName = "embedded-url.txt"
+)
+
+// EmbeddingSettings matches articles no keyword caught by their similarity to
+// example writeups of each category. The examples are embedded once with
+// "embeddings build" and stored in embeddings.json; each unmatched article
+// then costs one embedding request.
+type EmbeddingSettings struct {
+	Provider string `yaml:"provider"`
+	URL      string `yaml:"url"`
+	Model    string `yaml:"model"`
+	APIKey   string `yaml:"api_key"`
+	// MinSimilarity is the cosine similarity an article needs to an example
+	MinSimilarity float64 `yaml:"min_similarity"`
+	// Examples are titles or short excerpts of writeups, by category
+	Examples map[string][]string `yaml:"examples"`
+}
+
+// EmbeddedExample is a stored example vector
+type EmbeddedExample struct {
+	Category string    `json:"category"`
+	Text     string    `json:"text"`
+	Vector   []float64 `json:"vector"`
+}
+
+// EmbeddingIndex is the content of embeddings.json
+type EmbeddingIndex struct {
+	Model    string            `json:"model"`
+	Examples []EmbeddedExample `json:"examples"`
+}
+
+func embeddingsEnabled() bool {
+	return settings.Embeddings.Provider != "" && len(settings.Embeddings.Examples) > 0
+}
+
+// embeddingIndex is read from disk the first time an article needs it
+var embeddingIndex struct {
+	once  sync.Once
+	index EmbeddingIndex
+}
+
+func readEmbeddingIndex(filename string) (EmbeddingIndex, error) {
+	var index EmbeddingIndex
+
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return index, nil
+	}
+	if err != nil {
+		return index, fmt.Errorf("reading %s: %w", filename, err)
+	}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return index, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+	return index, nil
+}
+
+func writeEmbeddingIndex(filename string, index EmbeddingIndex) error {
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding embeddings: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", filename, err)
+	}
+	return nil
+}
+
+// runEmbeddings handles "embeddings build", which embeds the configured
+// examples and stores their vectors
+func runEmbeddings(args []string) error {
+	if len(args) == 0 || args[0] != "build" {
+		return fmt.Errorf("usage: embeddings build")
+	}
+	if !embeddingsEnabled() {
+		return fmt.Errorf("no embeddings provider or examples configured in %s", settingsFileName)
+	}
+
+	categories := make([]string, 0, len(settings.Embeddings.Examples))
+	for category := range settings.Embeddings.Examples {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	var examples []EmbeddedExample
+	var texts []string
+	for _, category := range categories {
+		for _, text := range settings.Embeddings.Examples[category] {
+			examples = append(examples, EmbeddedExample{Category: category, Text: text})
+			texts = append(texts, text)
+		}
+	}
+
+	vectors, err := embed(context.Background(), texts)
+	if err != nil {
+		return err
+	}
+	if len(vectors) != len(examples) {
+		return fmt.Errorf("embedding provider returned %d vectors for %d examples", len(vectors), len(examples))
+	}
+	for i := range examples {
+		examples[i].Vector = vectors[i]
+	}
+
+	index := EmbeddingIndex{Model: settings.Embeddings.Model, Examples: examples}
+	if err := writeEmbeddingIndex(stateFileName(embeddingsFileName), index); err != nil {
+		return err
+	}
+	printStatus(fmt.Sprintf("Embedded %d examples in %d categories", len(examples), len(categories)), color.FgGreen)
+	return nil
+}
+
+// embeddingMatch tags an article no keyword matched with the categories whose
+// examples it resembles. Only categories the profile routes are considered.
+func embeddingMatch(ctx context.Context, item *gofeed.Item, extracted *ExtractedContent, keywords map[string]string) (*Article, error) {
+	embeddingIndex.once.Do(func() {
+		index, err := readEmbeddingIndex(stateFileName(embeddingsFileName))
+		if err != nil {
+			printError(err.Error())
+			return
+		}
+		if index.Model != settings.Embeddings.Model {
+			printError(fmt.Sprintf("%s was built with model %q, run \"embeddings build\"", embeddingsFileName, index.Model))
+			return
+		}
+		embeddingIndex.index = index
+	})
+	if len(embeddingIndex.index.Examples) == 0 {
+		return nil, nil
+	}
+
+	text := articleText(item, extracted)
+	lowerText := strings.ToLower(text)
+	if _, excluded := excludedBy(text, lowerText, settings.Exclude); excluded {
+		return nil, nil
+	}
+
+	known := make(map[string]struct{})
+	for keyword := range keywords {
+		known[tagFor(keyword)] = struct{}{}
+	}
+
+	prompt := text
+	if len(prompt) > classifierMaxTextLen {
+		prompt = prompt[:classifierMaxTextLen]
+	}
+	vectors, err := embed(ctx, []string{prompt})
+	if err != nil {
+		return nil, err
+	}
+	if len(vectors) != 1 {
+		return nil, fmt.Errorf("embedding provider returned %d vectors", len(vectors))
+	}
+
+	minimum := settings.Embeddings.MinSimilarity
+	if minimum == 0 {
+		minimum = defaultEmbeddingMinimum
+	}
+
+	var tags []string
+	var best float64
+	tagged := make(map[string]struct{})
+	for _, example := range embeddingIndex.index.Examples {
+		if _, exists := known[example.Category]; !exists {
+			continue
+		}
+		if _, done := tagged[example.Category]; done {
+			continue
+		}
+		similarity := cosineSimilarity(vectors[0], example.Vector)
+		if similarity < minimum {
+			continue
+		}
+		if _, excluded := excludedBy(text, lowerText, excludedFor(example.Category)); excluded {
+			continue
+		}
+		tagged[example.Category] = struct{}{}
+		tags = append(tags, example.Category)
+		best = max(best, similarity)
+	}
+	if len(tags) == 0 {
+		return nil, nil
+	}
+
+	article := newArticle(item, extracted, text, tags, 0)
+	if article != nil {
+		article.Relevance = best
+	}
+	return article, nil
+}
+
+func cosineSimilarity(a, b []float64) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	var dot, normA, normB float64
+	for i := range a {
+		dot += a[i] * b[i]
+		normA += a[i] * a[i]
+		normB += b[i] * b[i]
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// embed requests vectors for texts from the configured provider
+func embed(ctx context.Context, texts []string) ([][]float64, error) {
+	cfg := settings.Embeddings
+
+	var endpoint string
+	switch cfg.Provider {
+	case classifierOpenAI:
+		endpoint = strings.TrimSuffix(firstNonEmpty(cfg.URL, defaultOpenAIURL), "/") + "/embeddings"
+	case classifierOllama:
+		endpoint = strings.TrimSuffix(firstNonEmpty(cfg.URL, defaultOllamaURL), "/") + "/api/embed"
+	default:
+		return nil, fmt.Errorf("unknown embeddings provider %q", cfg.Provider)
+	}
+
+	body, err := json.Marshal(map[string]any{"model": cfg.Model, "input": texts})
+	if err != nil {
+		return nil, fmt.Errorf("encoding embeddings request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, classifierTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("creating embeddings request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if cfg.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+cfg.APIKey)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("requesting embeddings: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, &HTTPError{StatusCode: resp.StatusCode, Body: body}
+	}
+
+	// OpenAI answers with data[].embedding, Ollama with embeddings[]
+	var result struct {
+		Data []struct {
+			Embedding []float64 `json:"embedding"`
+		} `json:"data"`
+		Embeddings [][]float64 `json:"embeddings"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("decoding embeddings response: %w", err)
+	}
+
+	vectors := result.Embeddings
+	for _, data := range result.Data {
+		vectors = append(vectors, data.Embedding)
+	}
+	return vectors, nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"log"
+	"os"
+)
+
+// Exit codes let cron and systemd tell a clean run from a degraded one.
+// Other errors, such as a failing subcommand, exit with 1 via log.Fatal.
+const (
+	exitClean = 0
+	// exitConfigError: the run could not start because of settings or
+	// environment, or "config validate" found problems
+	exitConfigError = 2
+	// exitFeedFailures: the run finished but some feeds failed
+	exitFeedFailures = 3
+	// exitNotifyFailed: messages were due but none reached Telegram
+	exitNotifyFailed = 4
+	// exitLocked: another run holds the instance lock
+	exitLocked = 5
+)
+
+// fatalConfig reports a configuration problem and exits with exitConfigError
+func fatalConfig(format string, args ...any) {
+	log.Printf(format, args...)
+	os.Exit(exitConfigError)
+}
+
+// runExitCode picks the exit code of a finished run; failed notifications
+// outrank failed feeds
+func runExitCode(summary RunSummary) int {
+	if nothingDelivered() {
+		return exitNotifyFailed
+	}
+	for _, profile := range summary.Profiles {
+		if profile.FailedFeeds > 0 || profile.Error != "" {
+			return exitFeedFailures
+		}
+	}
+	return exitClean
+}
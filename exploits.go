@@ -0,0 +1,102 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// Built-in exploit archive sources, listed in data.txt as "exploitdb:" and
+// "packetstorm:"
+const (
+	exploitDBPrefix   = "exploitdb:"
+	packetStormPrefix = "packetstorm:"
+	exploitDBFeed     = "https://www.exploit-db.com/rss.xml"
+	packetStormFeed   = "https://rss.packetstormsecurity.com/files/"
+)
+
+// ExploitInfo is the classification exploit archives publish with an entry
+type ExploitInfo struct {
+	Type     string
+	Platform string
+}
+
+// exploitTitleTag matches Exploit-DB's "[webapps] Title" prefix
+var exploitTitleTag = regexp.MustCompile(`^\s*\[([^\]]+)\]\s*`)
+
+// exploitTypes and exploitPlatforms recognize archive categories; anything
+// else in an entry's categories is ignored
+var (
+	exploitTypes = map[string]string{
+		"webapps": "webapps", "remote": "remote", "local": "local", "dos": "dos",
+		"shellcode": "shellcode", "exploit": "exploit", "advisory": "advisory",
+		"proof of concept": "poc", "poc": "poc",
+	}
+	exploitPlatforms = map[string]string{
+		"php": "PHP", "windows": "Windows", "linux": "Linux", "multiple": "Multiple",
+		"hardware": "Hardware", "android": "Android", "ios": "iOS", "macos": "macOS",
+		"java": "Java", "python": "Python", "asp": "ASP", "aspx": "ASPX",
+		"cgi": "CGI", "jsp": "JSP", "nodejs": "Node.js", "ruby": "Ruby",
+	}
+)
+
+// isExploitArchive recognizes the built-in exploit sources
+func isExploitArchive(feedURL string) bool {
+	return feedURL == exploitDBFeed || strings.HasPrefix(feedURL, packetStormFeed)
+}
+
+// classifyExploits reads exploit type and platform from titles and
+// categories and keeps them on the item for the message template
+func classifyExploits(items []*gofeed.Item) {
+	for _, item := range items {
+		var info ExploitInfo
+		if match := exploitTitleTag.FindStringSubmatch(item.Title); match != nil {
+			if exploitType, known := exploitTypes[strings.ToLower(match[1])]; known {
+				info.Type = exploitType
+				item.Title = strings.TrimSpace(item.Title[len(match[0]):])
+			}
+		}
+		for _, category := range item.Categories {
+			key := strings.ToLower(strings.TrimSpace(category))
+			if exploitType, known := exploitTypes[key]; known && info.Type == "" {
+				info.Type = exploitType
+			}
+			if platform, known := exploitPlatforms[key]; known && info.Platform == "" {
+				info.Platform = platform
+			}
+		}
+		if info == (ExploitInfo{}) {
+			continue
+		}
+
+		if item.Custom == nil {
+			item.Custom = make(map[string]string)
+		}
+		item.Custom["exploit_type"] = info.Type
+		item.Custom["exploit_platform"] = info.Platform
+	}
+}
+
+// exploitInfo returns what classifyExploits stored on an item, if anything
+func exploitInfo(item *gofeed.Item) *ExploitInfo {
+	if item.Custom == nil {
+		return nil
+	}
+	info := ExploitInfo{Type: item.Custom["exploit_type"], Platform: item.Custom["exploit_platform"]}
+	if info == (ExploitInfo{}) {
+		return nil
+	}
+	return &info
+}
+
+// String renders the classification for messages, e.g. "webapps, PHP"
+func (e *ExploitInfo) String() string {
+	var parts []string
+	for _, part := range []string{e.Type, e.Platform} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
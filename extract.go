@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	readability "github.com/go-shiori/go-readability"
+)
+
+const (
+	extractionTimeout = 30 * time.Second
+	wordsPerMinute    = 230
+)
+
+// ExtractedContent is what the per-domain extraction rules pulled from an article page
+type ExtractedContent struct {
+	Body    string
+	Author  string
+	Date    string
+	Excerpt string   // readability's summary of the page
+	HasPoC  bool     // code, commands or PoC repositories in the content
+	PoCs    []string // clone URLs of the PoC repositories the content links
+	Image   string   // og:image preview
+}
+
+// extractionRuleFor finds the rule for a link's domain, falling back from
+// "blog.example.com" to "example.com"
+func extractionRuleFor(link string) (ExtractionRule, bool) {
+	host := getDomain(link)
+	for host != "" {
+		if rule, exists := settings.Extraction[host]; exists {
+			return rule, true
+		}
+		dot := strings.Index(host, ".")
+		if dot < 0 {
+			break
+		}
+		host = host[dot+1:]
+	}
+	return ExtractionRule{}, false
+}
+
+// genericBodySelectors locate the main text of pages without an extraction rule
+var genericBodySelectors = []string{"article", "main", "[role=main]", "body"}
+
+// extractContent fetches an article page and applies the domain's CSS selectors.
+// Without a rule for the domain it reads the page's main text when fullBody or
+// readability is set, and otherwise returns nil. Callers check the lookback
+// first when the feed dates the item, so old items cost no request.
+func extractContent(ctx context.Context, link string, fullBody bool) (*ExtractedContent, error) {
+	rule, exists := extractionRuleFor(link)
+	if !exists && !fullBody && !settings.Readability {
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, extractionTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	setUserAgent(req)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching article: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &HTTPError{StatusCode: resp.StatusCode}
+	}
+
+	if !exists && settings.Readability {
+		return readableContent(resp, link)
+	}
+
+	doc, err := goquery.NewDocumentFromReader(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("parsing article HTML: %w", err)
+	}
+
+	if !exists {
+		image := ogImage(doc, link)
+		body := genericBody(doc)
+		return &ExtractedContent{Body: body, HasPoC: detectPoC(doc.Selection), PoCs: pocLinks(doc.Selection), Image: image}, nil
+	}
+
+	content := doc.Selection
+	if rule.Body != "" {
+		content = doc.Find(rule.Body)
+	}
+	return &ExtractedContent{
+		Body:   selectText(doc, rule.Body),
+		Author: selectText(doc, rule.Author),
+		Date:   selectDate(doc, rule.Date),
+		HasPoC: detectPoC(content),
+		PoCs:   pocLinks(content),
+		Image:  ogImage(doc, link),
+	}, nil
+}
+
+func selectText(doc *goquery.Document, selector string) string {
+	if selector == "" {
+		return ""
+	}
+	return strings.Join(strings.Fields(doc.Find(selector).Text()), " ")
+}
+
+// readableContent extracts the main content of a page with readability
+func readableContent(resp *http.Response, link string) (*ExtractedContent, error) {
+	pageURL, err := url.Parse(link)
+	if err != nil {
+		return nil, fmt.Errorf("parsing article URL: %w", err)
+	}
+	article, err := readability.FromReader(resp.Body, pageURL)
+	if err != nil {
+		return nil, fmt.Errorf("extracting readable content: %w", err)
+	}
+
+	content := &ExtractedContent{
+		Body:    strings.Join(strings.Fields(article.TextContent), " "),
+		Author:  strings.TrimSpace(article.Byline),
+		Excerpt: strings.TrimSpace(article.Excerpt),
+		Image:   strings.TrimSpace(article.Image),
+	}
+	if article.PublishedTime != nil {
+		content.Date = article.PublishedTime.Format(time.RFC3339)
+	}
+	if article.Node != nil {
+		node := goquery.NewDocumentFromNode(article.Node).Selection
+		content.HasPoC = detectPoC(node)
+		content.PoCs = pocLinks(node)
+	}
+	return content, nil
+}
+
+// readingTime estimates how long an article takes to read, at least a minute
+func readingTime(words int) string {
+	minutes := max((words+wordsPerMinute/2)/wordsPerMinute, 1)
+	return fmt.Sprintf("%d min read", minutes)
+}
+
+// ogImage returns the page's og:image (or twitter:image) as an absolute URL
+func ogImage(doc *goquery.Document, link string) string {
+	for _, selector := range []string{`meta[property="og:image"]`, `meta[name="twitter:image"]`} {
+		content, exists := doc.Find(selector).First().Attr("content")
+		if !exists || strings.TrimSpace(content) == "" {
+			continue
+		}
+		base, err := url.Parse(link)
+		if err != nil {
+			return ""
+		}
+		image, err := base.Parse(strings.TrimSpace(content))
+		if err != nil {
+			return ""
+		}
+		return image.String()
+	}
+	return ""
+}
+
+// genericBody returns the text of the first main content element, leaving
+// out scripts and page chrome
+func genericBody(doc *goquery.Document) string {
+	doc.Find("script, style, noscript, nav, header, footer, aside, form").Remove()
+	for _, selector := range genericBodySelectors {
+		if sel := doc.Find(selector).First(); sel.Length() > 0 {
+			return strings.Join(strings.Fields(sel.Text()), " ")
+		}
+	}
+	return ""
+}
+
+// selectDate prefers machine-readable datetime/content attributes over display text
+func selectDate(doc *goquery.Document, selector string) string {
+	if selector == "" {
+		return ""
+	}
+	sel := doc.Find(selector).First()
+	for _, attr := range []string{"datetime", "content"} {
+		if value, exists := sel.Attr(attr); exists {
+			return strings.TrimSpace(value)
+		}
+	}
+	return strings.TrimSpace(sel.Text())
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// FailoverSettings names a secondary destination used when no Telegram
+// message got through during a whole run: another chat, a webhook receiving
+// the undelivered messages as JSON, or an email sent over SMTP
+type FailoverSettings struct {
+	ChatID  string        `yaml:"chat_id"`
+	Webhook string        `yaml:"webhook"`
+	Email   EmailSettings `yaml:"email"`
+}
+
+// EmailSettings configures the SMTP failover; Port defaults to 587
+type EmailSettings struct {
+	Host     string   `yaml:"host"`
+	Port     int      `yaml:"port"`
+	Username string   `yaml:"username"`
+	Password string   `yaml:"password"`
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+}
+
+const defaultSMTPPort = 587
+
+// failedNotification is a message the primary channel did not accept
+type failedNotification struct {
+	Text      string `json:"text"`
+	ParseMode string `json:"-"`
+}
+
+// telegramRun counts deliveries and failures of the current run
+var telegramRun struct {
+	mu        sync.Mutex
+	delivered int
+	failed    []failedNotification
+}
+
+// resetTelegramRun starts counting afresh for a new run
+func resetTelegramRun() {
+	telegramRun.mu.Lock()
+	defer telegramRun.mu.Unlock()
+	telegramRun.delivered = 0
+	telegramRun.failed = nil
+}
+
+func recordTelegramDelivery() {
+	telegramRun.mu.Lock()
+	defer telegramRun.mu.Unlock()
+	telegramRun.delivered++
+}
+
+func recordTelegramFailure(text, parseMode string) {
+	metricTelegramFailure()
+	telegramRun.mu.Lock()
+	defer telegramRun.mu.Unlock()
+	telegramRun.failed = append(telegramRun.failed, failedNotification{Text: text, ParseMode: parseMode})
+}
+
+// nothingDelivered reports a run whose messages all failed to reach Telegram
+func nothingDelivered() bool {
+	telegramRun.mu.Lock()
+	defer telegramRun.mu.Unlock()
+	return telegramRun.delivered == 0 && len(telegramRun.failed) > 0
+}
+
+func (f FailoverSettings) configured() bool {
+	return f.ChatID != "" || f.Webhook != "" || f.Email.Host != ""
+}
+
+// failover hands the run's undelivered messages to the secondary destinations
+// when the primary channel accepted nothing. The messages stay in the outbox
+// so the primary channel still gets them once it is back.
+func failover(ctx context.Context, botToken string) {
+	if !nothingDelivered() || !settings.Failover.configured() {
+		return
+	}
+	telegramRun.mu.Lock()
+	failed := telegramRun.failed
+	telegramRun.mu.Unlock()
+
+	printStatus(fmt.Sprintf("Telegram unreachable this run; failing over %d message(s)", len(failed)), color.FgYellow)
+
+	if chatID := settings.Failover.ChatID; chatID != "" {
+		for _, notification := range failed {
+			message := TelegramMessage{ChatID: chatID, Text: notification.Text, ParseMode: notification.ParseMode}
+			if _, err := deliverTelegram(ctx, fmt.Sprintf(telegramMethodTemplate, botToken, "sendMessage"), message); err != nil {
+				printError(fmt.Sprintf("failover chat: %v", err))
+				break
+			}
+		}
+	}
+	if settings.Failover.Webhook != "" {
+		if err := postFailoverWebhook(ctx, settings.Failover.Webhook, failed); err != nil {
+			printError(fmt.Sprintf("failover webhook: %v", err))
+		}
+	}
+	if settings.Failover.Email.Host != "" {
+		if err := sendFailoverEmail(settings.Failover.Email, failed); err != nil {
+			printError(fmt.Sprintf("failover email: %v", err))
+		}
+	}
+}
+
+func postFailoverWebhook(ctx context.Context, webhook string, failed []failedNotification) error {
+	payload, err := json.Marshal(map[string]any{
+		"reason":   "telegram unreachable",
+		"time":     time.Now().UTC(),
+		"messages": failed,
+	})
+	if err != nil {
+		return fmt.Errorf("encoding payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	setUserAgent(req)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("posting: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &HTTPError{StatusCode: resp.StatusCode, Body: body}
+	}
+	return nil
+}
+
+func sendFailoverEmail(cfg EmailSettings, failed []failedNotification) error {
+	port := cfg.Port
+	if port == 0 {
+		port = defaultSMTPPort
+	}
+
+	var body strings.Builder
+	fmt.Fprintf(&body, "From: %s\r\n", cfg.From)
+	fmt.Fprintf(&body, "To: %s\r\n", strings.Join(cfg.To, ", "))
+	fmt.Fprintf(&body, "Subject: %s%d undelivered writeup alerts\r\n", environmentPrefix(), len(failed))
+	body.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	body.WriteString("Telegram could not be reached during this run. These alerts are queued and will be resent there:\r\n\r\n")
+	for _, notification := range failed {
+		body.WriteString(strings.ReplaceAll(notification.Text, "\n", "\r\n"))
+		body.WriteString("\r\n\r\n")
+	}
+
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	addr := net.JoinHostPort(cfg.Host, strconv.Itoa(port))
+	return smtp.SendMail(addr, auth, cfg.From, cfg.To, []byte(body.String()))
+}
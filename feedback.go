@@ -0,0 +1,286 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+const (
+	feedbackFileName = "feedback.json"
+	feedbackSave     = "save"
+	feedbackIgnore   = "irrelevant"
+	feedbackTimeout  = 30 * time.Second
+)
+
+// InlineKeyboardMarkup is the reply_markup of a message with buttons
+type InlineKeyboardMarkup struct {
+	InlineKeyboard [][]InlineKeyboardButton `json:"inline_keyboard"`
+}
+
+// InlineKeyboardButton opens a URL or sends callback data back to the bot
+type InlineKeyboardButton struct {
+	Text         string `json:"text"`
+	URL          string `json:"url,omitempty"`
+	CallbackData string `json:"callback_data,omitempty"`
+}
+
+// Feedback is one button press on a notification
+type Feedback struct {
+	Action string    `json:"action"`
+	Link   string    `json:"link"`
+	Title  string    `json:"title,omitempty"`
+	Tags   []string  `json:"tags,omitempty"`
+	User   string    `json:"user,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// FeedbackState holds recorded feedback and the next Telegram update to read
+type FeedbackState struct {
+	Offset  int        `json:"offset"`
+	Entries []Feedback `json:"entries"`
+}
+
+// articleID shortens a link to fit Telegram's 64-byte callback data
+func articleID(link string) string {
+	sum := sha256.Sum256([]byte(link))
+	return hex.EncodeToString(sum[:8])
+}
+
+// articleKeyboard builds the Open / Archived copy / Save / Not relevant buttons
+func articleKeyboard(article *Article) *InlineKeyboardMarkup {
+	id := articleID(article.Link)
+	links := []InlineKeyboardButton{{Text: "Open", URL: cleanURL(article.Link)}}
+	if article.ArchiveURL != "" {
+		links = append(links, InlineKeyboardButton{Text: "Archived copy", URL: article.ArchiveURL})
+	}
+	return &InlineKeyboardMarkup{InlineKeyboard: [][]InlineKeyboardButton{
+		links,
+		{
+			{Text: "Save", CallbackData: feedbackSave + ":" + id},
+			{Text: "Not relevant", CallbackData: feedbackIgnore + ":" + id},
+		},
+	}}
+}
+
+func readFeedbackState(filename string) (FeedbackState, error) {
+	var state FeedbackState
+
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, fmt.Errorf("reading %s: %w", filename, err)
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+	return state, nil
+}
+
+func writeFeedbackState(filename string, state FeedbackState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding feedback: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", filename, err)
+	}
+	return nil
+}
+
+// runFeedback handles the button feedback subcommands:
+//
+//	feedback poll     records button presses since the last poll
+//	feedback report   summarizes feedback per tag for keyword tuning
+func runFeedback(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: feedback poll|report")
+	}
+
+	filename := stateFileName(feedbackFileName)
+	state, err := readFeedbackState(filename)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "poll":
+		botToken := getEnvForEnvironment("TELEGRAM_BOT_TOKEN")
+		if botToken == "" {
+			return fmt.Errorf("TELEGRAM_BOT_TOKEN environment variable not set")
+		}
+		recorded, err := pollFeedback(context.Background(), botToken, &state)
+		if err != nil {
+			return err
+		}
+		printStatus(fmt.Sprintf("Recorded %d feedback entries", recorded), color.FgGreen)
+		return writeFeedbackState(filename, state)
+	case "report":
+		reportFeedback(state.Entries)
+		return nil
+	}
+	return fmt.Errorf("unknown feedback command %q", args[0])
+}
+
+type telegramUpdate struct {
+	UpdateID      int `json:"update_id"`
+	CallbackQuery *struct {
+		ID   string `json:"id"`
+		Data string `json:"data"`
+		From struct {
+			Username  string `json:"username"`
+			FirstName string `json:"first_name"`
+		} `json:"from"`
+	} `json:"callback_query"`
+}
+
+// pollFeedback reads pending callback queries, records them against the
+// stored articles and acknowledges each press
+func pollFeedback(ctx context.Context, botToken string, state *FeedbackState) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, feedbackTimeout)
+	defer cancel()
+
+	params := url.Values{}
+	params.Set("offset", strconv.Itoa(state.Offset))
+	params.Set("allowed_updates", `["callback_query"]`)
+	var updates []telegramUpdate
+	if err := telegramGet(ctx, botToken, "getUpdates", params, &updates); err != nil {
+		return 0, err
+	}
+
+	articles, err := storedArticlesByID()
+	if err != nil {
+		return 0, err
+	}
+
+	recorded := 0
+	for _, update := range updates {
+		state.Offset = update.UpdateID + 1
+		query := update.CallbackQuery
+		if query == nil {
+			continue
+		}
+
+		action, id, _ := strings.Cut(query.Data, ":")
+		answer := "Thanks, noted"
+		if article, exists := articles[id]; exists && (action == feedbackSave || action == feedbackIgnore) {
+			state.Entries = append(state.Entries, Feedback{
+				Action: action,
+				Link:   article.Link,
+				Title:  article.Title,
+				Tags:   article.Tags,
+				User:   firstNonEmpty(query.From.Username, query.From.FirstName),
+				At:     time.Now().UTC(),
+			})
+			recorded++
+		} else {
+			answer = "Article not found"
+		}
+
+		answerParams := url.Values{}
+		answerParams.Set("callback_query_id", query.ID)
+		answerParams.Set("text", answer)
+		if err := telegramGet(ctx, botToken, "answerCallbackQuery", answerParams, nil); err != nil {
+			printError(fmt.Sprintf("Answering callback: %v", err))
+		}
+	}
+	return recorded, nil
+}
+
+// storedArticlesByID indexes every article store, including the profiles', by
+// the ID used in button callbacks
+func storedArticlesByID() (map[string]StoredArticle, error) {
+	ext := filepath.Ext(articleStoreFileName)
+	files, err := filepath.Glob(strings.TrimSuffix(articleStoreFileName, ext) + "*" + ext)
+	if err != nil {
+		return nil, err
+	}
+
+	articles := make(map[string]StoredArticle)
+	for _, file := range files {
+		records, err := readStoredArticles(file)
+		if err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			articles[articleID(record.Link)] = record
+		}
+	}
+	return articles, nil
+}
+
+// telegramGet calls a Bot API method and decodes its result into out
+func telegramGet(ctx context.Context, botToken, method string, params url.Values, out any) error {
+	endpoint := fmt.Sprintf(telegramMethodTemplate, botToken, method) + "?" + params.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("creating %s request: %w", method, err)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("calling %s: %w", method, stripBotURL(err))
+	}
+	defer resp.Body.Close()
+
+	var result telegramResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("decoding %s response: %w", method, err)
+	}
+	if !result.OK {
+		return fmt.Errorf("%s: %s", method, result.Description)
+	}
+	if out != nil {
+		if err := json.Unmarshal(result.Result, out); err != nil {
+			return fmt.Errorf("decoding %s result: %w", method, err)
+		}
+	}
+	return nil
+}
+
+// reportFeedback prints saves and "not relevant" presses per tag, so noisy
+// keywords stand out
+func reportFeedback(entries []Feedback) {
+	type counts struct{ saved, irrelevant int }
+	byTag := make(map[string]*counts)
+	for _, entry := range entries {
+		for _, tag := range entry.Tags {
+			if byTag[tag] == nil {
+				byTag[tag] = &counts{}
+			}
+			if entry.Action == feedbackSave {
+				byTag[tag].saved++
+			} else {
+				byTag[tag].irrelevant++
+			}
+		}
+	}
+
+	tags := make([]string, 0, len(byTag))
+	for tag := range byTag {
+		tags = append(tags, tag)
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return byTag[tags[i]].irrelevant > byTag[tags[j]].irrelevant ||
+			(byTag[tags[i]].irrelevant == byTag[tags[j]].irrelevant && tags[i] < tags[j])
+	})
+
+	fmt.Printf("%-30s %6s %13s\n", "TAG", "SAVED", "NOT RELEVANT")
+	for _, tag := range tags {
+		fmt.Printf("%-30s %6d %13d\n", tag, byTag[tag].saved, byTag[tag].irrelevant)
+	}
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	feedHealthFileName     = "feed-health.json"
+	defaultQuarantineAfter = 30 * 24 * time.Hour
+)
+
+// FeedHealthSettings controls when a failing feed is quarantined
+type FeedHealthSettings struct {
+	// QuarantineAfter is how long a feed may keep failing (default 720h)
+	QuarantineAfter time.Duration `yaml:"quarantine_after"`
+}
+
+// FeedHealth is the failure history of one feed across runs
+type FeedHealth struct {
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	FailingSince        time.Time `json:"failing_since,omitempty"`
+	LastError           string    `json:"last_error,omitempty"`
+	LastSuccess         time.Time `json:"last_success,omitempty"`
+	Quarantined         bool      `json:"quarantined,omitempty"`
+}
+
+// recordFeedFailure counts a failed fetch and reports whether the feed has
+// now been failing long enough to be quarantined
+func recordFeedFailure(health map[string]*FeedHealth, feedURL string, fetchErr error, now time.Time) bool {
+	h, exists := health[feedURL]
+	if !exists {
+		h = &FeedHealth{}
+		health[feedURL] = h
+	}
+	if h.ConsecutiveFailures == 0 {
+		h.FailingSince = now
+	}
+	h.ConsecutiveFailures++
+	h.LastError = fetchErr.Error()
+
+	quarantineAfter := settings.FeedHealth.QuarantineAfter
+	if quarantineAfter <= 0 {
+		quarantineAfter = defaultQuarantineAfter
+	}
+	if !h.Quarantined && now.Sub(h.FailingSince) >= quarantineAfter {
+		h.Quarantined = true
+		return true
+	}
+	return false
+}
+
+// recordFeedSuccess clears a feed's failure history
+func recordFeedSuccess(health map[string]*FeedHealth, feedURL string, now time.Time) {
+	health[feedURL] = &FeedHealth{LastSuccess: now}
+}
+
+// quarantined reports whether a feed has been auto-disabled
+func quarantined(health map[string]*FeedHealth, feedURL string) bool {
+	h, exists := health[feedURL]
+	return exists && h.Quarantined
+}
+
+func readFeedHealth(filename string) (map[string]*FeedHealth, error) {
+	health := make(map[string]*FeedHealth)
+
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return health, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", filename, err)
+	}
+
+	if err := json.Unmarshal(data, &health); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+	return health, nil
+}
+
+func writeFeedHealth(filename string, health map[string]*FeedHealth) error {
+	data, err := json.MarshalIndent(health, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding feed health: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", filename, err)
+	}
+	return nil
+}
+
+// runHealth reports failing and quarantined feeds of the profiles selected
+// with --profile:
+//
+//	health                   lists every feed that is currently failing
+//	health restore <url>     takes a feed out of quarantine
+func runHealth(args []string) error {
+	if len(args) > 0 && (args[0] != "restore" || len(args) != 2) {
+		return fmt.Errorf("usage: health [restore <url>]")
+	}
+	profiles, err := commandProfiles()
+	if err != nil {
+		return err
+	}
+
+	if len(args) > 0 {
+		restored := false
+		for _, profile := range profiles {
+			filename := profile.stateFile(feedHealthFileName)
+			health, err := readFeedHealth(filename)
+			if err != nil {
+				return err
+			}
+			if _, exists := health[args[1]]; !exists {
+				continue
+			}
+			delete(health, args[1])
+			if err := writeFeedHealth(filename, health); err != nil {
+				return err
+			}
+			restored = true
+		}
+		if !restored {
+			return fmt.Errorf("no health record for %s", args[1])
+		}
+		return nil
+	}
+
+	for _, profile := range profiles {
+		health, err := readFeedHealth(profile.stateFile(feedHealthFileName))
+		if err != nil {
+			return err
+		}
+		if err := printHealth(profile, health); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// printHealth lists one profile's failing feeds
+func printHealth(profile Profile, health map[string]*FeedHealth) error {
+	var failing []string
+	for feedURL, h := range health {
+		if h.ConsecutiveFailures > 0 {
+			failing = append(failing, feedURL)
+		}
+	}
+	if len(failing) == 0 {
+		fmt.Println(profile.label() + "All feeds healthy")
+		return nil
+	}
+	// Longest-failing feeds first
+	sort.Slice(failing, func(i, j int) bool {
+		return health[failing[i]].FailingSince.Before(health[failing[j]].FailingSince)
+	})
+
+	if profile.Name != "" {
+		fmt.Printf("[%s]\n", profile.Name)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STATE\tFAILURES\tFAILING SINCE\tFEED\tLAST ERROR")
+	for _, feedURL := range failing {
+		h := health[feedURL]
+		state := "failing"
+		if h.Quarantined {
+			state = "DEAD"
+		}
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n", state, h.ConsecutiveFailures,
+			h.FailingSince.Format("2006-01-02"), feedURL, h.LastError)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,178 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// remoteListPrefix marks a data.txt line that references a remote feed list,
+// e.g. "list https://gist.githubusercontent.com/.../feeds.txt sha256:ab12..."
+const (
+	remoteListPrefix  = "list "
+	remoteListPinTag  = "sha256:"
+	remoteListTimeout = 30 * time.Second
+)
+
+// RemoteList is a feed list hosted elsewhere, optionally pinned to a hash
+type RemoteList struct {
+	URL    string
+	SHA256 string
+}
+
+// parseRemoteList parses a "list <url> [sha256:<hex>]" line
+func parseRemoteList(line string) (RemoteList, error) {
+	fields := strings.Fields(strings.TrimPrefix(line, remoteListPrefix))
+	if len(fields) == 0 || len(fields) > 2 {
+		return RemoteList{}, fmt.Errorf("malformed list entry: %q", line)
+	}
+
+	list := RemoteList{URL: fields[0]}
+	if len(fields) == 2 {
+		if !strings.HasPrefix(fields[1], remoteListPinTag) {
+			return RemoteList{}, fmt.Errorf("unknown pin %q for list %s", fields[1], list.URL)
+		}
+		list.SHA256 = strings.ToLower(strings.TrimPrefix(fields[1], remoteListPinTag))
+	}
+	return list, nil
+}
+
+// fetchRemoteList downloads a remote feed list and verifies its pin if set
+func fetchRemoteList(list RemoteList) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteListTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, list.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request for list %s: %w", list.URL, err)
+	}
+	setUserAgent(req)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching list %s: %w", list.URL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &HTTPError{StatusCode: resp.StatusCode}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading list %s: %w", list.URL, err)
+	}
+
+	if list.SHA256 != "" {
+		sum := sha256.Sum256(body)
+		if got := hex.EncodeToString(sum[:]); got != list.SHA256 {
+			return nil, fmt.Errorf("list %s hash mismatch: pinned %s, got %s", list.URL, list.SHA256, got)
+		}
+	}
+
+	var urls []string
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		// Nested lists are not followed to keep startup bounded
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, remoteListPrefix) {
+			continue
+		}
+		urls = append(urls, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scanning list %s: %w", list.URL, err)
+	}
+
+	return urls, nil
+}
+
+// expandShorthand turns source shorthand such as "medium:tag:xss" or
+// "reddit:netsec" into feed URLs; other entries are returned unchanged
+func expandShorthand(entry string) ([]string, error) {
+	switch {
+	case strings.HasPrefix(entry, mediumPrefix):
+		return expandMediumEntry(entry)
+	case strings.HasPrefix(entry, redditPrefix):
+		return expandRedditEntry(entry)
+	case strings.HasPrefix(entry, hackerNewsPrefix):
+		return expandHackerNewsEntry(entry)
+	case strings.HasPrefix(entry, twitterPrefix):
+		return expandTwitterEntry(entry)
+	case strings.HasPrefix(entry, youtubePrefix):
+		return expandYouTubeEntry(entry)
+	case strings.HasPrefix(entry, githubPrefix):
+		return expandGitHubEntry(entry)
+	case strings.HasPrefix(entry, nvdPrefix):
+		return expandNVDEntry(entry)
+	case strings.HasPrefix(entry, pluginPrefix):
+		return expandPluginEntry(entry)
+	case strings.HasPrefix(entry, imapPrefix):
+		return expandIMAPEntry(entry)
+	case strings.HasPrefix(entry, rssHubPrefix):
+		return expandRSSHubEntry(entry)
+	case strings.HasPrefix(entry, telegramSourcePrefix):
+		return expandTelegramSourceEntry(entry)
+	case entry == exploitDBPrefix:
+		return []string{exploitDBFeed}, nil
+	case entry == packetStormPrefix:
+		return []string{packetStormFeed}, nil
+	}
+	return []string{entry}, nil
+}
+
+// expandFeedList replaces remote list entries with their contents, expands
+// source shorthand and drops duplicates. A list that fails to fetch or verify
+// is reported and skipped so local feeds still run.
+func expandFeedList(entries []string) []string {
+	seen := make(map[string]struct{})
+	var urls []string
+
+	add := func(u string) {
+		feeds, err := expandShorthand(u)
+		if err != nil {
+			printError(err.Error())
+			return
+		}
+		for _, feed := range feeds {
+			if _, exists := seen[feed]; exists {
+				continue
+			}
+			seen[feed] = struct{}{}
+			urls = append(urls, feed)
+		}
+	}
+
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry, remoteListPrefix) {
+			add(entry)
+			continue
+		}
+
+		list, err := parseRemoteList(entry)
+		if err != nil {
+			printError(err.Error())
+			continue
+		}
+
+		remote, err := fetchRemoteList(list)
+		if err != nil {
+			printError(fmt.Sprintf("Skipping remote feed list: %v", err))
+			continue
+		}
+
+		for _, u := range remote {
+			add(u)
+		}
+	}
+
+	return urls
+}
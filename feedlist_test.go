@@ -0,0 +1,75 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseRemoteList(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    RemoteList
+		wantErr bool
+	}{
+		{line: "list https://example.com/feeds.txt", want: RemoteList{URL: "https://example.com/feeds.txt"}},
+		{line: "list https://example.com/feeds.txt sha256:AB12", want: RemoteList{URL: "https://example.com/feeds.txt", SHA256: "ab12"}},
+		{line: "list   https://example.com/feeds.txt  ", want: RemoteList{URL: "https://example.com/feeds.txt"}},
+		{line: "list ", wantErr: true},
+		{line: "list https://example.com/feeds.txt md5:ab12", wantErr: true},
+		{line: "list https://example.com/feeds.txt sha256:ab12 extra", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseRemoteList(tt.line)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseRemoteList(%q) error = %v, want error %v", tt.line, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseRemoteList(%q) = %+v, want %+v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestFetchRemoteList(t *testing.T) {
+	body := "# security feeds\nhttps://a.example/feed\n\nlist https://nested.example/list.txt\nhttps://b.example/rss\n"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	sum := sha256.Sum256([]byte(body))
+	pin := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name    string
+		sha256  string
+		want    []string
+		wantErr string
+	}{
+		{name: "unpinned", want: []string{"https://a.example/feed", "https://b.example/rss"}},
+		{name: "pinned", sha256: pin, want: []string{"https://a.example/feed", "https://b.example/rss"}},
+		{name: "mismatch", sha256: strings.Repeat("0", 64), wantErr: "hash mismatch"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := fetchRemoteList(RemoteList{URL: server.URL, SHA256: tt.sha256})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("fetchRemoteList error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("fetchRemoteList = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// slowFeedsTag is the tag the slowest feeds report goes out under; give it
+	// a thread with routes or a destination's threads
+	slowFeedsTag       = "maintenance"
+	defaultSlowFeedTop = 5
+)
+
+// SlowFeedSettings posts the slowest feeds of each run so the feed list can
+// be curated
+type SlowFeedSettings struct {
+	Report bool `yaml:"report"`
+	Top    int  `yaml:"top"`
+}
+
+// FeedTiming is how long fetching and parsing one feed took
+type FeedTiming struct {
+	URL      string
+	Duration time.Duration
+	Failed   bool
+}
+
+// slowestFeeds returns the p95 fetch time and the top slowest feeds
+func slowestFeeds(timings []FeedTiming, top int) (time.Duration, []FeedTiming) {
+	sorted := append([]FeedTiming(nil), timings...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Duration > sorted[j].Duration })
+
+	durations := make([]time.Duration, len(sorted))
+	for i, timing := range sorted {
+		durations[len(sorted)-1-i] = timing.Duration
+	}
+	if top > len(sorted) {
+		top = len(sorted)
+	}
+	return percentile(durations, 95), sorted[:top]
+}
+
+// formatSlowFeeds renders the slowest feeds report
+func formatSlowFeeds(timings []FeedTiming, top int) string {
+	p95, slowest := slowestFeeds(timings, top)
+	var b strings.Builder
+	fmt.Fprintf(&b, "Feed fetch times: p95 %s over %d feeds. Slowest:", p95.Round(time.Millisecond), len(timings))
+	for _, timing := range slowest {
+		fmt.Fprintf(&b, "\n%s %s", timing.Duration.Round(time.Millisecond), timing.URL)
+		if timing.Failed {
+			b.WriteString(" (failed)")
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Words shorter than this must match exactly even with fuzzy matching, so
+// short terms like "xss" or "idor" do not pick up unrelated words
+const minFuzzyWordLength = 5
+
+// stemSuffixes are stripped longest first; a small suffix stripper is enough
+// to fold the inflections seen in writeup titles
+var stemSuffixes = []string{
+	"ations", "ation", "ating", "ated", "ates", "ate",
+	"ions", "ion", "ings", "ing", "ers", "er", "ed", "es", "s",
+}
+
+// approximateOptionsFor resolves stemming and fuzzy distance for a term
+func approximateOptionsFor(term string) (stem bool, distance int) {
+	layers := matchLayers(term)
+	for _, layer := range layers {
+		if layer.Stem != nil {
+			stem = *layer.Stem
+			break
+		}
+	}
+	for _, layer := range layers {
+		if layer.Fuzzy != nil {
+			distance = *layer.Fuzzy
+			break
+		}
+	}
+	return stem, min(max(distance, 0), 2)
+}
+
+// stemWord strips one inflectional suffix, keeping at least four letters.
+// A double s is not a plural, so "bypass" stays as it is.
+func stemWord(word string) string {
+	for _, suffix := range stemSuffixes {
+		if suffix == "s" && strings.HasSuffix(word, "ss") {
+			continue
+		}
+		if strings.HasSuffix(word, suffix) && len(word)-len(suffix) >= 4 {
+			return strings.TrimSuffix(word, suffix)
+		}
+	}
+	return word
+}
+
+// splitWords lowercases text and splits it on anything but letters and digits
+func splitWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// approximateMatch looks for the term's words, in order and adjacent, among
+// the text's words, comparing stems and/or allowing a small edit distance
+func approximateMatch(lowerText, term string, stem bool, distance int) bool {
+	termWords := splitWords(term)
+	if len(termWords) == 0 {
+		return false
+	}
+	textWords := splitWords(lowerText)
+	if stem {
+		for i := range termWords {
+			termWords[i] = stemWord(termWords[i])
+		}
+		for i := range textWords {
+			textWords[i] = stemWord(textWords[i])
+		}
+	}
+
+	for start := 0; start+len(termWords) <= len(textWords); start++ {
+		matched := true
+		for i, want := range termWords {
+			if !similarWords(textWords[start+i], want, distance) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// similarWords compares two words allowing the given edit distance for long ones
+func similarWords(a, b string, distance int) bool {
+	if a == b {
+		return true
+	}
+	if distance == 0 || len(a) < minFuzzyWordLength || len(b) < minFuzzyWordLength {
+		return false
+	}
+	return editDistance(a, b, distance) <= distance
+}
+
+// editDistance computes the Levenshtein distance between two words, giving up
+// with limit+1 once the words differ by more than limit
+func editDistance(a, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	if abs(len(ra)-len(rb)) > limit {
+		return limit + 1
+	}
+
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		best := current[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+			best = min(best, current[j])
+		}
+		if best > limit {
+			return limit + 1
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
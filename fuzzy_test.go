@@ -0,0 +1,130 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestStemWord(t *testing.T) {
+	tests := []struct {
+		word, want string
+	}{
+		{"authentication", "authentic"},
+		{"authenticated", "authentic"},
+		{"bypasses", "bypass"},
+		{"bypassing", "bypass"},
+		{"injection", "inject"},
+		{"injections", "inject"},
+		{"tokens", "token"},
+		{"bypass", "bypass"},
+		{"access", "access"},
+		// At least four letters are kept
+		{"uses", "uses"},
+		{"xss", "xss"},
+		{"idor", "idor"},
+	}
+	for _, tt := range tests {
+		if got := stemWord(tt.word); got != tt.want {
+			t.Errorf("stemWord(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		limit int
+		want  int
+	}{
+		{"bypass", "bypass", 2, 0},
+		{"bypass", "bypas", 2, 1},
+		{"deserialization", "deserialisation", 2, 1},
+		{"kitten", "sitting", 3, 3},
+		{"kitten", "sitting", 2, 3},
+		{"short", "longerword", 1, 2},
+		{"", "abc", 3, 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b, tt.limit); got != tt.want {
+			t.Errorf("editDistance(%q, %q, %d) = %d, want %d", tt.a, tt.b, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestApproximateMatch(t *testing.T) {
+	tests := []struct {
+		text, term string
+		stem       bool
+		distance   int
+		want       bool
+	}{
+		{"Authenticated bypasses in the admin panel", "authentication bypass", true, 0, true},
+		{"Authenticated bypasses in the admin panel", "authentication bypass", false, 0, false},
+		{"Bypass of authentication", "authentication bypass", true, 0, false},
+		{"Insecure deserialisation in Java", "deserialization", false, 1, true},
+		{"Insecure deserialisation in Java", "deserialization", false, 0, false},
+		{"Prototype polution to RCE", "prototype pollution", false, 1, true},
+		{"Prototype polution to RCE", "prototype pollution", true, 1, true},
+		// Short words must match exactly even with a distance
+		{"An IDOB in the API", "idor", false, 2, false},
+		{"Stored XSS", "xss", false, 2, true},
+		{"Anything", "", true, 2, false},
+	}
+	for _, tt := range tests {
+		got := approximateMatch(strings.ToLower(tt.text), tt.term, tt.stem, tt.distance)
+		if got != tt.want {
+			t.Errorf("approximateMatch(%q, %q, stem %v, distance %d) = %v, want %v",
+				tt.text, tt.term, tt.stem, tt.distance, got, tt.want)
+		}
+	}
+}
+
+func TestMatchesKeywordApproximate(t *testing.T) {
+	stem := true
+	fuzzy := 1
+	tooFuzzy := 5
+
+	tests := []struct {
+		name     string
+		settings Settings
+		text     string
+		term     string
+		want     bool
+	}{
+		{
+			name: "exact by default",
+			text: "Authenticated bypasses",
+			term: "authentication bypass",
+			want: false,
+		},
+		{
+			name:     "global stemming",
+			settings: Settings{Matching: MatchOptions{Stem: &stem}},
+			text:     "Authenticated bypasses",
+			term:     "authentication bypass",
+			want:     true,
+		},
+		{
+			name:     "per keyword fuzzy",
+			settings: Settings{KeywordOptions: map[string]MatchOptions{"deserialization": {Fuzzy: &fuzzy}}},
+			text:     "Java deserialisation gadget",
+			term:     "deserialization",
+			want:     true,
+		},
+		{
+			name:     "fuzzy capped at two",
+			settings: Settings{Matching: MatchOptions{Fuzzy: &tooFuzzy}},
+			text:     "Java serialisation gadget",
+			term:     "deserialization",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useSettings(t, tt.settings)
+			if got := matchesKeyword(tt.text, strings.ToLower(tt.text), tt.term); got != tt.want {
+				t.Errorf("matchesKeyword(%q, %q) = %v, want %v", tt.text, tt.term, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/mmcdole/gofeed"
+)
+
+// githubPrefix marks data.txt shorthand for watched GitHub files, typically
+// curated writeup lists:
+//
+//	github:owner/repo                  watches README.md
+//	github:owner/repo/path/to/list.md  watches that file
+//
+// Links added to the file since the last run become articles; the first run
+// only records what is already there.
+const (
+	githubPrefix         = "github:"
+	githubAPIBase        = "https://api.github.com/repos/"
+	githubWatchFileName  = "github-watch.json"
+	defaultGitHubWatched = "README.md"
+)
+
+// GitHubSettings authenticates API calls; anonymous calls are limited to 60 an hour
+type GitHubSettings struct {
+	Token string `yaml:"token"`
+}
+
+// githubWatch maps a watched contents URL to the links seen in the file, so
+// only additions are reported. It is loaded and saved around each run.
+var githubWatch = map[string][]string{}
+
+// markdownLink matches [text](https://...) links
+var markdownLink = regexp.MustCompile(`\[([^\]]+)\]\((https?://[^)\s]+)\)`)
+
+// expandGitHubEntry turns a github: line into its contents API URL
+func expandGitHubEntry(entry string) ([]string, error) {
+	parts := strings.SplitN(strings.Trim(strings.TrimPrefix(entry, githubPrefix), "/ "), "/", 3)
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("malformed github entry: %q", entry)
+	}
+	path := defaultGitHubWatched
+	if len(parts) == 3 && parts[2] != "" {
+		path = parts[2]
+	}
+	return []string{fmt.Sprintf("%s%s/%s/contents/%s", githubAPIBase, parts[0], parts[1], path)}, nil
+}
+
+// isGitHubWatch recognizes contents API URLs of watched files
+func isGitHubWatch(feedURL string) bool {
+	return strings.HasPrefix(feedURL, githubAPIBase) && strings.Contains(feedURL, "/contents/")
+}
+
+// parseGitHubWatch returns the links added to a watched file since last time
+func parseGitHubWatch(ctx context.Context, body []byte, feedURL string, now time.Time) ([]*gofeed.Item, error) {
+	var file struct {
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+		HTMLURL  string `json:"html_url"`
+	}
+	if err := json.Unmarshal(body, &file); err != nil {
+		return nil, fmt.Errorf("unmarshaling GitHub contents: %w", err)
+	}
+	if file.Encoding != "base64" {
+		return nil, fmt.Errorf("unsupported GitHub content encoding %q", file.Encoding)
+	}
+	content, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(file.Content, "\n", ""))
+	if err != nil {
+		return nil, fmt.Errorf("decoding GitHub contents: %w", err)
+	}
+
+	feedStateMu.Lock()
+	previous, watched := githubWatch[feedURL]
+	feedStateMu.Unlock()
+	known := make(map[string]struct{}, len(previous))
+	for _, link := range previous {
+		known[link] = struct{}{}
+	}
+
+	var items []*gofeed.Item
+	var links []string
+	for _, match := range markdownLink.FindAllStringSubmatch(string(content), -1) {
+		title, link := strings.TrimSpace(match[1]), match[2]
+		if _, exists := known[link]; exists {
+			links = append(links, link)
+			continue
+		}
+		known[link] = struct{}{}
+		links = append(links, link)
+		if !watched {
+			continue
+		}
+		// Added links carry the time they were noticed so the lookback keeps them
+		items = append(items, &gofeed.Item{
+			Title:       title,
+			Description: "Added to " + file.HTMLURL,
+			Link:        link,
+			Published:   now.UTC().Format(time.RFC1123Z),
+		})
+	}
+	feedStateMu.Lock()
+	if ctx.Err() == nil {
+		githubWatch[feedURL] = links
+	}
+	feedStateMu.Unlock()
+
+	if !watched {
+		printStatus(fmt.Sprintf("Recorded %d existing links in %s", len(links), file.HTMLURL), color.FgBlue)
+	}
+	return items, nil
+}
+
+func readGitHubWatch(filename string) (map[string][]string, error) {
+	watch := make(map[string][]string)
+
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return watch, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", filename, err)
+	}
+	if err := json.Unmarshal(data, &watch); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+	return watch, nil
+}
+
+func writeGitHubWatch(filename string, watch map[string][]string) error {
+	data, err := json.MarshalIndent(watch, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding GitHub watch state: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", filename, err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"html"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// googleAlertsFeedPrefix is the path of every Google Alerts RSS feed
+const googleAlertsFeedPrefix = "https://www.google.com/alerts/feeds/"
+
+// htmlTag strips the <b> highlighting Google puts around matched terms
+var htmlTag = regexp.MustCompile(`<[^>]+>`)
+
+// isGoogleAlertsFeed recognizes Google Alerts RSS feeds
+func isGoogleAlertsFeed(feedURL string) bool {
+	return strings.HasPrefix(feedURL, googleAlertsFeedPrefix)
+}
+
+// unwrapGoogleAlerts replaces google.com/url redirect links with their
+// target minus tracking parameters, so alerts dedup against the same article
+// seen through other feeds, and removes highlighting markup from the text
+func unwrapGoogleAlerts(items []*gofeed.Item) {
+	for _, item := range items {
+		item.Link = cleanURL(unwrapGoogleRedirect(item.Link))
+		item.Title = html.UnescapeString(htmlTag.ReplaceAllString(item.Title, ""))
+		item.Description = html.UnescapeString(htmlTag.ReplaceAllString(item.Description, ""))
+	}
+}
+
+// unwrapGoogleRedirect returns the url= target of a google.com/url link
+func unwrapGoogleRedirect(link string) string {
+	u, err := url.Parse(link)
+	if err != nil || !strings.HasSuffix(u.Hostname(), "google.com") || u.Path != "/url" {
+		return link
+	}
+	for _, param := range []string{"url", "q"} {
+		if target := u.Query().Get(param); strings.HasPrefix(target, "http") {
+			return target
+		}
+	}
+	return link
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// hackerNewsPrefix marks data.txt shorthand for Hacker News searches through
+// the Algolia API; each comma-separated term becomes its own query, e.g.
+// "hn:writeup,CVE-,bug bounty"
+const (
+	hackerNewsPrefix    = "hn:"
+	hackerNewsSearchURL = "https://hn.algolia.com/api/v1/search_by_date"
+	hackerNewsItemURL   = "https://news.ycombinator.com/item?id="
+)
+
+// expandHackerNewsEntry turns an hn: line into one search URL per term
+func expandHackerNewsEntry(entry string) ([]string, error) {
+	var feeds []string
+	for _, term := range strings.Split(strings.TrimPrefix(entry, hackerNewsPrefix), ",") {
+		term = strings.TrimSpace(term)
+		if term == "" {
+			continue
+		}
+		query := url.Values{}
+		query.Set("query", term)
+		query.Set("tags", "story")
+		query.Set("hitsPerPage", "50")
+		feeds = append(feeds, hackerNewsSearchURL+"?"+query.Encode())
+	}
+	if len(feeds) == 0 {
+		return nil, fmt.Errorf("hn entry %q names no search terms", entry)
+	}
+	return feeds, nil
+}
+
+// isHackerNewsSearch recognizes Algolia HN search URLs
+func isHackerNewsSearch(feedURL string) bool {
+	return strings.HasPrefix(feedURL, "https://hn.algolia.com/api/")
+}
+
+type hackerNewsResults struct {
+	Hits []struct {
+		ObjectID   string `json:"objectID"`
+		Title      string `json:"title"`
+		URL        string `json:"url"`
+		Author     string `json:"author"`
+		StoryText  string `json:"story_text"`
+		CreatedAtI int64  `json:"created_at_i"`
+	} `json:"hits"`
+}
+
+// parseHackerNewsResults maps hits to items linking to the story itself, so
+// writeups already seen through their blog's feed dedup against it; text-only
+// posts link to the discussion
+func parseHackerNewsResults(body []byte) ([]*gofeed.Item, error) {
+	var results hackerNewsResults
+	if err := json.Unmarshal(body, &results); err != nil {
+		return nil, fmt.Errorf("unmarshaling HN results: %w", err)
+	}
+
+	var items []*gofeed.Item
+	for _, hit := range results.Hits {
+		discussion := hackerNewsItemURL + hit.ObjectID
+		link := hit.URL
+		if link == "" {
+			link = discussion
+		}
+
+		item := &gofeed.Item{
+			Title:       hit.Title,
+			Description: hit.StoryText,
+			Link:        link,
+			GUID:        "hn-" + hit.ObjectID,
+			Published:   time.Unix(hit.CreatedAtI, 0).UTC().Format(time.RFC1123Z),
+		}
+		if hit.Author != "" {
+			item.Author = &gofeed.Person{Name: hit.Author}
+		}
+		if link != discussion {
+			item.Links = []string{link, discussion}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// minHealthStaleAfter keeps frequent schedules from flapping the check
+	minHealthStaleAfter = time.Hour
+	// scheduleSampleRuns is how many upcoming runs staleAfter looks at
+	scheduleSampleRuns = 32
+)
+
+// healthStaleness is how long runs may go on without fetching a feed before
+// /healthz fails, set from the schedules whenever serve (re)loads them
+var healthStaleness struct {
+	mu    sync.Mutex
+	after time.Duration
+}
+
+// staleAfter is hunter.yaml's health_stale_after or, by default, twice the
+// longest gap between upcoming runs of any schedule plus the run timeout, so
+// a daily or weekly profile is not reported stale between its runs
+func staleAfter(schedules []schedule, runTimeout time.Duration, now time.Time) time.Duration {
+	if settings.HealthStaleAfter > 0 {
+		return settings.HealthStaleAfter
+	}
+	var longest time.Duration
+	for _, sched := range schedules {
+		previous := now
+		for range scheduleSampleRuns {
+			next := sched.next(previous)
+			if next.IsZero() {
+				break
+			}
+			longest = max(longest, next.Sub(previous))
+			previous = next
+		}
+	}
+	return max(2*longest+runTimeout, minHealthStaleAfter)
+}
+
+func setHealthStaleness(after time.Duration) {
+	healthStaleness.mu.Lock()
+	defer healthStaleness.mu.Unlock()
+	healthStaleness.after = after
+}
+
+// serveStarted is when the daemon started, the baseline before any run succeeds
+var serveStarted = time.Now()
+
+// HealthReport is the JSON body of /healthz and /readyz
+type HealthReport struct {
+	Status         string    `json:"status"`
+	LastRun        time.Time `json:"last_run,omitempty"`
+	LastSuccess    time.Time `json:"last_success,omitempty"`
+	QueueDepth     int       `json:"queue_depth"`
+	ConfigProblems []string  `json:"config_problems,omitempty"`
+}
+
+// metricRunSucceeded records a run in which at least one feed was fetched
+func metricRunSucceeded() {
+	runMetrics.mu.Lock()
+	defer runMetrics.mu.Unlock()
+	runMetrics.lastRunSucceeded = time.Now()
+}
+
+func healthReport() HealthReport {
+	runMetrics.mu.Lock()
+	report := HealthReport{
+		Status:      "ok",
+		LastRun:     runMetrics.lastRunFinished,
+		LastSuccess: runMetrics.lastRunSucceeded,
+	}
+	runMetrics.mu.Unlock()
+
+	report.QueueDepth = outbox.pending()
+	return report
+}
+
+// handleHealthz is the liveness check: it fails once runs have gone on
+// for the staleness period without fetching a single feed
+func handleHealthz(w http.ResponseWriter, r *http.Request) {
+	report := healthReport()
+	since := report.LastSuccess
+	if since.IsZero() {
+		since = serveStarted
+	}
+	healthStaleness.mu.Lock()
+	after := healthStaleness.after
+	healthStaleness.mu.Unlock()
+
+	status := http.StatusOK
+	if !report.LastRun.IsZero() && after > 0 && time.Since(since) > after {
+		report.Status = "stale"
+		status = http.StatusServiceUnavailable
+	}
+	writeHealth(w, status, report)
+}
+
+// handleReadyz is the readiness check: hunter.yaml on disk must load and
+// validate, so a broken edit shows up before the next run trips over it
+func handleReadyz(w http.ResponseWriter, r *http.Request) {
+	report := healthReport()
+	s, err := loadSettings(settingsFileName)
+	if err != nil {
+		report.ConfigProblems = []string{err.Error()}
+	} else {
+		report.ConfigProblems = validateSettings(s)
+	}
+	status := http.StatusOK
+	if len(report.ConfigProblems) > 0 {
+		report.Status = "invalid config"
+		status = http.StatusServiceUnavailable
+	}
+	writeHealth(w, status, report)
+}
+
+func writeHealth(w http.ResponseWriter, status int, report HealthReport) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(report)
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestStaleAfter(t *testing.T) {
+	// Wednesday
+	now := time.Date(2025, 1, 15, 10, 7, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		specs      []string
+		runTimeout time.Duration
+		override   time.Duration
+		want       time.Duration
+	}{
+		{name: "hourly", specs: []string{"@hourly"}, want: 2 * time.Hour},
+		{name: "frequent runs floor at an hour", specs: []string{"*/5 * * * *"}, want: minHealthStaleAfter},
+		{name: "daily", specs: []string{"@daily"}, want: 48 * time.Hour},
+		{name: "weekly beside hourly", specs: []string{"@hourly", "@weekly"}, want: 14 * 24 * time.Hour},
+		{name: "weekdays span the weekend", specs: []string{"0 9 * * 1-5"}, want: 6 * 24 * time.Hour},
+		{name: "run timeout added", specs: []string{"@daily"}, runTimeout: 30 * time.Minute, want: 48*time.Hour + 30*time.Minute},
+		{name: "configured", specs: []string{"@weekly"}, override: 36 * time.Hour, want: 36 * time.Hour},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useSettings(t, Settings{HealthStaleAfter: tt.override})
+			var schedules []schedule
+			for _, spec := range tt.specs {
+				sched, err := parseSchedule(spec)
+				if err != nil {
+					t.Fatal(err)
+				}
+				schedules = append(schedules, sched)
+			}
+			if got := staleAfter(schedules, tt.runTimeout, now); got != tt.want {
+				t.Errorf("staleAfter(%v) = %v, want %v", tt.specs, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const heartbeatTimeout = 10 * time.Second
+
+// HeartbeatSettings pings a dead man's switch (healthchecks.io, Cronitor and
+// the like) after every successful run, so monitoring notices when runs stop.
+// FailURL, if set, is pinged instead after a run that fetched no feed.
+type HeartbeatSettings struct {
+	URL     string `yaml:"url"`
+	FailURL string `yaml:"fail_url"`
+}
+
+// sendHeartbeat pings the heartbeat URL matching the run's outcome
+func sendHeartbeat(ctx context.Context, succeeded bool) {
+	target := settings.Heartbeat.URL
+	if !succeeded {
+		target = settings.Heartbeat.FailURL
+	}
+	if target == "" || dryRun {
+		return
+	}
+	if err := pingHeartbeat(ctx, target); err != nil {
+		printError(fmt.Sprintf("Error sending heartbeat: %v", err))
+	}
+}
+
+func pingHeartbeat(ctx context.Context, target string) error {
+	ctx, cancel := context.WithTimeout(ctx, heartbeatTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	setUserAgent(req)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("pinging %s: %w", target, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &HTTPError{StatusCode: resp.StatusCode, Body: body}
+	}
+	return nil
+}
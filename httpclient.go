@@ -0,0 +1,230 @@
+package main
+
+import (
+	"compress/gzip"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/andybalholm/brotli"
+)
+
+const (
+	// defaultMaxResponseMB caps a decoded response body; a feed returning
+	// hundreds of megabytes would otherwise be read into memory whole
+	defaultMaxResponseMB = 20
+
+	defaultHTTPTimeout  = time.Minute
+	defaultMaxRedirects = 10
+)
+
+// HTTPSettings tunes the shared client used for feeds, pages and Telegram
+type HTTPSettings struct {
+	// Timeout bounds a single request including reading the body
+	Timeout time.Duration `yaml:"timeout"`
+
+	// Connection pool limits; zero keeps Go's defaults
+	MaxIdleConns        int           `yaml:"max_idle_conns"`
+	MaxIdleConnsPerHost int           `yaml:"max_idle_conns_per_host"`
+	MaxConnsPerHost     int           `yaml:"max_conns_per_host"`
+	IdleConnTimeout     time.Duration `yaml:"idle_conn_timeout"`
+	TLSHandshakeTimeout time.Duration `yaml:"tls_handshake_timeout"`
+
+	// MaxRedirects stops redirect chains early (default 10); -1 disables redirects
+	MaxRedirects int `yaml:"max_redirects"`
+
+	// TLS: MinVersion is "1.2" or "1.3"; CAFile adds a PEM bundle to the
+	// system roots, e.g. for an intercepting corporate proxy
+	MinTLSVersion      string `yaml:"min_tls_version"`
+	CAFile             string `yaml:"ca_file"`
+	InsecureSkipVerify bool   `yaml:"insecure_skip_verify"`
+
+	// DNS swaps in a custom resolver or DNS-over-HTTPS; lookups are cached
+	// either way
+	DNS DNSSettings `yaml:"dns"`
+}
+
+// errResponseTooLarge is returned by reads past the response size limit
+var errResponseTooLarge = errors.New("response body exceeds size limit")
+
+// httpClient is shared by every fetch path and the Telegram sender. Its
+// transport negotiates gzip and brotli itself, decodes them and bounds the
+// decoded body size. configureHTTPClient applies hunter.yaml's http section.
+var httpClient = &http.Client{
+	Transport: &decodingTransport{base: http.DefaultTransport},
+	Timeout:   defaultHTTPTimeout,
+}
+
+// configureHTTPClient rebuilds the shared client from settings.HTTP
+func configureHTTPClient() error {
+	transport, err := newHTTPTransport(settings.HTTP)
+	if err != nil {
+		return err
+	}
+
+	httpClient.Transport = &decodingTransport{base: transport}
+	httpClient.Timeout = defaultHTTPTimeout
+	if settings.HTTP.Timeout > 0 {
+		httpClient.Timeout = settings.HTTP.Timeout
+	}
+
+	maxRedirects := settings.HTTP.MaxRedirects
+	if maxRedirects == 0 {
+		maxRedirects = defaultMaxRedirects
+	}
+	httpClient.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if len(via) > maxRedirects {
+			return fmt.Errorf("stopped after %d redirects", maxRedirects)
+		}
+		return nil
+	}
+	return nil
+}
+
+// newHTTPTransport clones Go's default transport with the pool and TLS settings
+func newHTTPTransport(cfg HTTPSettings) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = proxyForRequest
+
+	resolver, err := newCachingResolver(cfg.DNS, &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+	transport.DialContext = resolver.DialContext
+
+	if cfg.MaxIdleConns > 0 {
+		transport.MaxIdleConns = cfg.MaxIdleConns
+	}
+	if cfg.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	}
+	if cfg.MaxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = cfg.MaxConnsPerHost
+	}
+	if cfg.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = cfg.IdleConnTimeout
+	}
+	if cfg.TLSHandshakeTimeout > 0 {
+		transport.TLSHandshakeTimeout = cfg.TLSHandshakeTimeout
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: cfg.InsecureSkipVerify}
+	switch cfg.MinTLSVersion {
+	case "":
+	case "1.2":
+		tlsConfig.MinVersion = tls.VersionTLS12
+	case "1.3":
+		tlsConfig.MinVersion = tls.VersionTLS13
+	default:
+		return nil, fmt.Errorf("http.min_tls_version: unsupported version %q", cfg.MinTLSVersion)
+	}
+	if cfg.CAFile != "" {
+		pem, err := os.ReadFile(cfg.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("http.ca_file: %w", err)
+		}
+		roots, err := x509.SystemCertPool()
+		if err != nil {
+			roots = x509.NewCertPool()
+		}
+		if !roots.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("http.ca_file: no certificates in %s", cfg.CAFile)
+		}
+		tlsConfig.RootCAs = roots
+	}
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// decodingTransport wraps a transport with explicit content decoding and a
+// body size limit. Setting Accept-Encoding disables Go's transparent gzip, so
+// both encodings are handled here.
+type decodingTransport struct {
+	base http.RoundTripper
+}
+
+func (t *decodingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Header.Get("Accept-Encoding") == "" && req.Method != http.MethodHead {
+		req = req.Clone(req.Context())
+		req.Header.Set("Accept-Encoding", "gzip, br")
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body := resp.Body
+	switch encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))); encoding {
+	case "", "identity":
+	case "gzip", "x-gzip":
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			body.Close()
+			return nil, fmt.Errorf("decoding gzip response: %w", err)
+		}
+		body = &decodedBody{Reader: gz, raw: resp.Body}
+	case "br":
+		body = &decodedBody{Reader: brotli.NewReader(body), raw: resp.Body}
+	default:
+		body.Close()
+		return nil, fmt.Errorf("unsupported content encoding %q", encoding)
+	}
+	if body != resp.Body {
+		resp.Header.Del("Content-Encoding")
+		resp.Header.Del("Content-Length")
+		resp.ContentLength = -1
+		resp.Uncompressed = true
+	}
+
+	resp.Body = &limitedBody{ReadCloser: body, remaining: maxResponseBytes()}
+	return resp, nil
+}
+
+// maxResponseBytes is the configured response size limit
+func maxResponseBytes() int64 {
+	mb := settings.MaxResponseMB
+	if mb <= 0 {
+		mb = defaultMaxResponseMB
+	}
+	return int64(mb) << 20
+}
+
+// decodedBody closes the underlying connection body of a decompressing reader
+type decodedBody struct {
+	io.Reader
+	raw io.Closer
+}
+
+func (b *decodedBody) Close() error {
+	return b.raw.Close()
+}
+
+// limitedBody fails reads once more than remaining bytes were read, rather
+// than silently truncating like io.LimitReader
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, errResponseTooLarge
+	}
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return n, errResponseTooLarge
+	}
+	return n, err
+}
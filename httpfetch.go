@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const feedValidatorsFileName = "feed-validators.json"
+
+// errNotModified reports a 304 answer to a conditional feed request
+var errNotModified = errors.New("feed not modified")
+
+// FeedValidators are the cache validators a feed returned on its last 200
+type FeedValidators struct {
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+// feedValidators is loaded at the start of a run and saved at its end
+var feedValidators = map[string]FeedValidators{}
+
+// pendingValidators holds what the current feed's responses returned. They
+// replace feedValidators only once the feed was processed, so a feed that
+// fails after its 200 is downloaded in full again instead of answering 304.
+var pendingValidators = map[string]FeedValidators{}
+
+// commitFeedValidators keeps the validators of the feed just processed
+func commitFeedValidators() {
+	feedStateMu.Lock()
+	defer feedStateMu.Unlock()
+	for feedURL, validators := range pendingValidators {
+		if validators.ETag != "" || validators.LastModified != "" {
+			feedValidators[feedURL] = validators
+		} else {
+			delete(feedValidators, feedURL)
+		}
+	}
+	clear(pendingValidators)
+}
+
+// discardFeedValidators forgets the validators of a feed that failed
+func discardFeedValidators() {
+	feedStateMu.Lock()
+	defer feedStateMu.Unlock()
+	clear(pendingValidators)
+}
+
+func readFeedValidators(filename string) (map[string]FeedValidators, error) {
+	validators := make(map[string]FeedValidators)
+
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return validators, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", filename, err)
+	}
+	if err := json.Unmarshal(data, &validators); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+	return validators, nil
+}
+
+func writeFeedValidators(filename string, validators map[string]FeedValidators) error {
+	data, err := json.MarshalIndent(validators, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding feed validators: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", filename, err)
+	}
+	return nil
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// fetchFeedBody serves a feed from the HTTP cache while fresh, otherwise
+// downloads it with a conditional GET. It returns errNotModified on 304 and
+// holds the validators of a 200 until commitFeedValidators.
+func fetchFeedBody(ctx context.Context, feedURL string) ([]byte, error) {
+	if body, fresh := loadCachedResponse(settings.HTTPCache.Dir, feedURL, time.Now()); fresh {
+		return body, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+
+	setUserAgent(req)
+	applyFeedAuth(req, feedURL)
+
+	feedStateMu.Lock()
+	previous, exists := feedValidators[feedURL]
+	feedStateMu.Unlock()
+	if exists {
+		if previous.ETag != "" {
+			req.Header.Set("If-None-Match", previous.ETag)
+		}
+		if previous.LastModified != "" {
+			req.Header.Set("If-Modified-Since", previous.LastModified)
+		}
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching feed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, errNotModified
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		httpErr := &HTTPError{StatusCode: resp.StatusCode, Body: body}
+		if isRateLimited(resp.StatusCode) {
+			httpErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		}
+		return nil, httpErr
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+
+	if err := storeCachedResponse(settings.HTTPCache.Dir, feedURL, resp.Header, body, time.Now()); err != nil {
+		printError(fmt.Sprintf("Caching %s: %v", feedURL, err))
+	}
+
+	validators := FeedValidators{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+	feedStateMu.Lock()
+	// A fetch abandoned by the watchdog no longer records anything
+	if ctx.Err() == nil {
+		pendingValidators[feedURL] = validators
+	}
+	feedStateMu.Unlock()
+
+	return body, nil
+}
@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestFeedValidatorsWaitForProcessing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("<rss></rss>"))
+	}))
+	defer server.Close()
+
+	saved := feedValidators
+	t.Cleanup(func() { feedValidators = saved })
+	feedValidators = map[string]FeedValidators{}
+
+	// A feed that fails after its 200 is fetched in full again
+	if _, err := fetchFeedBody(context.Background(), server.URL); err != nil {
+		t.Fatal(err)
+	}
+	if _, exists := feedValidators[server.URL]; exists {
+		t.Fatal("validators stored before the feed was processed")
+	}
+	discardFeedValidators()
+	if _, err := fetchFeedBody(context.Background(), server.URL); err != nil {
+		t.Fatalf("refetch after a failed feed: %v", err)
+	}
+
+	// Once processed, the next request is conditional
+	commitFeedValidators()
+	if got := feedValidators[server.URL].ETag; got != `"v1"` {
+		t.Fatalf("stored ETag = %q, want %q", got, `"v1"`)
+	}
+	if _, err := fetchFeedBody(context.Background(), server.URL); !errors.Is(err, errNotModified) {
+		t.Fatalf("conditional refetch: got %v, want errNotModified", err)
+	}
+}
+
+func TestFetchFeedBodyAbandoned(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("<rss></rss>"))
+	}))
+	defer server.Close()
+	discardFeedValidators()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	fetchFeedBody(ctx, server.URL)
+	feedStateMu.Lock()
+	defer feedStateMu.Unlock()
+	if len(pendingValidators) != 0 {
+		t.Errorf("cancelled fetch recorded validators: %v", pendingValidators)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"   ", 0},
+		{"0", 0},
+		{"120", 2 * time.Minute},
+		{" 30 ", 30 * time.Second},
+		{"-5", 0},
+		{"soon", 0},
+		{"Wed, 15 Jan 2025 10:01:30 GMT", 90 * time.Second},
+		{"Wed, 15 Jan 2025 09:59:00 GMT", 0},
+		{"Wed, 15 Jan 2025 10:00:00 GMT", 0},
+	}
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
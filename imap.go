@@ -0,0 +1,227 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/emersion/go-imap"
+	"github.com/emersion/go-imap/client"
+	_ "github.com/emersion/go-message/charset"
+	"github.com/emersion/go-message/mail"
+	"github.com/mmcdole/gofeed"
+)
+
+// imapPrefix marks data.txt shorthand for newsletter folders on the
+// configured mailbox, e.g. "imap:Newsletters/tldrsec,Newsletters/BugBytes".
+// Every link in recent mails becomes an article candidate.
+const (
+	imapPrefix      = "imap:"
+	imapScheme      = "imap://"
+	imapWindow      = 7 * 24 * time.Hour
+	imapMaxMessages = 50
+)
+
+// IMAPSettings is the mailbox newsletters are delivered to; Address is
+// host:port of an IMAPS (TLS) server
+type IMAPSettings struct {
+	Address  string `yaml:"address"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// newsletterBoilerplate skips links that are part of every newsletter
+var newsletterBoilerplate = regexp.MustCompile(`(?i)unsubscribe|view (it )?in (your )?browser|manage (your )?(preferences|subscription)|update your profile|privacy policy|forward to a friend`)
+
+// plainTextURL finds links in text/plain parts
+var plainTextURL = regexp.MustCompile(`https?://[^\s<>"')\]]+`)
+
+// expandIMAPEntry turns an imap: line into one imap:// URL per folder
+func expandIMAPEntry(entry string) ([]string, error) {
+	if settings.IMAP.Address == "" {
+		return nil, fmt.Errorf("%s needs imap.address in %s", entry, settingsFileName)
+	}
+	var feeds []string
+	for _, folder := range strings.Split(strings.TrimPrefix(entry, imapPrefix), ",") {
+		if folder = strings.TrimSpace(folder); folder != "" {
+			feeds = append(feeds, imapScheme+settings.IMAP.Address+"/"+url.PathEscape(folder))
+		}
+	}
+	if len(feeds) == 0 {
+		return nil, fmt.Errorf("imap entry %q names no folders", entry)
+	}
+	return feeds, nil
+}
+
+// isIMAPSource recognizes mailbox folder sources
+func isIMAPSource(feedURL string) bool {
+	return strings.HasPrefix(feedURL, imapScheme)
+}
+
+// validateIMAPSource checks an imap:// source names a host:port mailbox and a
+// folder, and that the mailbox has credentials
+func validateIMAPSource(feedURL string) error {
+	address, escapedFolder, _ := strings.Cut(strings.TrimPrefix(feedURL, imapScheme), "/")
+	if _, _, err := net.SplitHostPort(address); err != nil {
+		return fmt.Errorf("invalid IMAP address in %q: %v", feedURL, err)
+	}
+	if folder, err := url.PathUnescape(escapedFolder); err != nil || folder == "" {
+		return fmt.Errorf("invalid IMAP folder in %q", feedURL)
+	}
+	if settings.IMAP.Username == "" || settings.IMAP.Password == "" {
+		return fmt.Errorf("%s needs imap.username and imap.password in %s", feedURL, settingsFileName)
+	}
+	return nil
+}
+
+// fetchIMAP reads the folder's mails from the last week, read-only so
+// nothing is marked as seen, and returns the links they contain
+func fetchIMAP(ctx context.Context, feedURL string) ([]*gofeed.Item, error) {
+	address, escapedFolder, _ := strings.Cut(strings.TrimPrefix(feedURL, imapScheme), "/")
+	folder, err := url.PathUnescape(escapedFolder)
+	if err != nil {
+		return nil, fmt.Errorf("parsing folder: %w", err)
+	}
+
+	conn, err := (&tls.Dialer{}).DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to %s: %w", address, err)
+	}
+	// The IMAP client has no context support; closing the connection unblocks it
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+	c, err := client.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("connecting to %s: %w", address, err)
+	}
+	defer c.Logout()
+
+	if err := c.Login(settings.IMAP.Username, settings.IMAP.Password); err != nil {
+		return nil, fmt.Errorf("logging in: %w", err)
+	}
+	if _, err := c.Select(folder, true); err != nil {
+		return nil, fmt.Errorf("selecting %s: %w", folder, err)
+	}
+
+	criteria := imap.NewSearchCriteria()
+	criteria.Since = time.Now().Add(-imapWindow)
+	uids, err := c.UidSearch(criteria)
+	if err != nil {
+		return nil, fmt.Errorf("searching %s: %w", folder, err)
+	}
+	if len(uids) == 0 {
+		return nil, nil
+	}
+	if len(uids) > imapMaxMessages {
+		uids = uids[len(uids)-imapMaxMessages:]
+	}
+
+	seqset := new(imap.SeqSet)
+	seqset.AddNum(uids...)
+	section := &imap.BodySectionName{Peek: true}
+	messages := make(chan *imap.Message, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- c.UidFetch(seqset, []imap.FetchItem{section.FetchItem(), imap.FetchEnvelope}, messages)
+	}()
+
+	var items []*gofeed.Item
+	for message := range messages {
+		body := message.GetBody(section)
+		if body == nil {
+			continue
+		}
+		newsletterItems, err := parseNewsletter(body)
+		if err != nil {
+			printError(fmt.Sprintf("Error parsing mail in %s: %v", folder, err))
+			continue
+		}
+		items = append(items, newsletterItems...)
+	}
+	if err := <-done; err != nil {
+		return nil, fmt.Errorf("fetching mails from %s: %w", folder, err)
+	}
+	return items, nil
+}
+
+// parseNewsletter extracts the links of one mail, preferring its HTML part
+func parseNewsletter(r io.Reader) ([]*gofeed.Item, error) {
+	reader, err := mail.CreateReader(r)
+	if err != nil {
+		return nil, err
+	}
+	subject, _ := reader.Header.Subject()
+	published := ""
+	if date, err := reader.Header.Date(); err == nil {
+		published = date.Format(time.RFC1123Z)
+	}
+
+	var htmlBody, textBody []byte
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		header, inline := part.Header.(*mail.InlineHeader)
+		if !inline {
+			continue
+		}
+		contentType, _, _ := header.ContentType()
+		data, err := io.ReadAll(part.Body)
+		if err != nil {
+			return nil, err
+		}
+		switch contentType {
+		case "text/html":
+			htmlBody = data
+		case "text/plain":
+			textBody = data
+		}
+	}
+
+	seen := make(map[string]struct{})
+	var items []*gofeed.Item
+	add := func(link, text string) {
+		text = strings.Join(strings.Fields(text), " ")
+		if _, exists := seen[link]; exists || newsletterBoilerplate.MatchString(text+" "+link) {
+			return
+		}
+		seen[link] = struct{}{}
+		items = append(items, &gofeed.Item{
+			Title:       firstNonEmpty(text, link),
+			Description: subject,
+			Link:        link,
+			Published:   published,
+		})
+	}
+
+	if htmlBody != nil {
+		doc, err := goquery.NewDocumentFromReader(bytes.NewReader(htmlBody))
+		if err != nil {
+			return nil, err
+		}
+		doc.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+			if href := a.AttrOr("href", ""); strings.HasPrefix(href, "http") {
+				add(href, a.Text())
+			}
+		})
+		return items, nil
+	}
+	for _, link := range plainTextURL.FindAllString(string(textBody), -1) {
+		add(strings.TrimRight(link, ".,;"), "")
+	}
+	return items, nil
+}
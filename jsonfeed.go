@@ -0,0 +1,157 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+const feedTypeJSON = "json"
+
+// JSONMapping locates item fields in an arbitrary JSON API response. Paths are
+// dot-separated keys ("data.posts", "author.name"); a path crossing an array
+// collects the field from every element, and "$." and "[]" are accepted for
+// readability ("$.data.posts[].title").
+type JSONMapping struct {
+	Items       string `yaml:"items"` // the array of items; empty for a top-level array
+	Title       string `yaml:"title"`
+	Link        string `yaml:"link"`
+	Published   string `yaml:"published"` // a date string or Unix seconds
+	Description string `yaml:"description"`
+	Author      string `yaml:"author"`
+	Tags        string `yaml:"tags"`
+}
+
+// writeupsXYZMapping reads writeups.xyz's index.json and the indexes other
+// hunters publish in the same format
+var writeupsXYZMapping = JSONMapping{
+	Title:       "title",
+	Link:        "link",
+	Published:   "published",
+	Description: "description",
+	Author:      "authors.name",
+	Tags:        "vulnerabilities.title",
+}
+
+// parseJSONFeed maps a JSON document to items using the mapping
+func parseJSONFeed(body []byte, mapping JSONMapping) ([]*gofeed.Item, error) {
+	var document any
+	if err := json.Unmarshal(body, &document); err != nil {
+		return nil, fmt.Errorf("unmarshaling JSON: %w", err)
+	}
+
+	var elements []any
+	for _, value := range jsonPath(document, mapping.Items) {
+		if array, ok := value.([]any); ok {
+			elements = append(elements, array...)
+		} else {
+			elements = append(elements, value)
+		}
+	}
+
+	var items []*gofeed.Item
+	for _, element := range elements {
+		item := &gofeed.Item{
+			Title:       jsonString(element, mapping.Title),
+			Link:        jsonString(element, mapping.Link),
+			Published:   jsonDate(element, mapping.Published),
+			Description: jsonString(element, mapping.Description),
+			Categories:  jsonStrings(element, mapping.Tags),
+		}
+		if item.Link == "" {
+			continue
+		}
+		if authors := jsonStrings(element, mapping.Author); len(authors) > 0 {
+			item.Author = &gofeed.Person{Name: strings.Join(authors, ", ")}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// jsonPath resolves a dotted path, fanning out over arrays along the way
+func jsonPath(value any, path string) []any {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	path = strings.ReplaceAll(strings.ReplaceAll(path, "[*]", ""), "[]", "")
+	if path == "" {
+		return []any{value}
+	}
+
+	key, rest, _ := strings.Cut(path, ".")
+	switch v := value.(type) {
+	case map[string]any:
+		child, exists := v[key]
+		if !exists {
+			return nil
+		}
+		return jsonPath(child, rest)
+	case []any:
+		var values []any
+		for _, element := range v {
+			values = append(values, jsonPath(element, path)...)
+		}
+		return values
+	}
+	return nil
+}
+
+// jsonStrings returns the scalar values at a path as strings
+func jsonStrings(value any, path string) []string {
+	if path == "" {
+		return nil
+	}
+	var values []string
+	for _, v := range jsonPath(value, path) {
+		switch s := v.(type) {
+		case string:
+			if s != "" {
+				values = append(values, s)
+			}
+		case float64:
+			values = append(values, strconv.FormatFloat(s, 'f', -1, 64))
+		case bool:
+			values = append(values, strconv.FormatBool(s))
+		case []any:
+			for _, element := range s {
+				if str, ok := element.(string); ok && str != "" {
+					values = append(values, str)
+				}
+			}
+		}
+	}
+	return values
+}
+
+func jsonString(value any, path string) string {
+	if values := jsonStrings(value, path); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// jsonDate normalizes Unix timestamps and bare dates to a format parseDate reads
+func jsonDate(value any, path string) string {
+	if path == "" {
+		return ""
+	}
+	for _, v := range jsonPath(value, path) {
+		switch d := v.(type) {
+		case float64:
+			// Millisecond timestamps are common in JavaScript APIs
+			if d > 1e12 {
+				d /= 1000
+			}
+			return time.Unix(int64(d), 0).UTC().Format(time.RFC1123Z)
+		case string:
+			if t, err := time.Parse("2006-01-02", d); err == nil {
+				return t.Format(time.RFC1123Z)
+			}
+			return d
+		}
+	}
+	return ""
+}
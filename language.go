@@ -0,0 +1,100 @@
+package main
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Minimum stopword hits before a Latin-script language is named; shorter
+// texts stay undetected and pass every filter
+const minLanguageHits = 2
+
+// languageStopwords are frequent function words that tell the Latin-script
+// languages writeups commonly appear in apart
+var languageStopwords = map[string][]string{
+	"en": {"the", "and", "of", "to", "is", "in", "that", "with", "for", "this", "how", "was", "from"},
+	"pt": {"de", "que", "não", "uma", "para", "com", "em", "os", "das", "como", "foi", "mais", "vulnerabilidade"},
+	"es": {"el", "la", "que", "los", "las", "una", "para", "con", "por", "del", "como", "fue", "vulnerabilidad"},
+	"fr": {"le", "la", "les", "des", "une", "est", "pour", "dans", "que", "avec", "sur", "du", "vulnérabilité"},
+	"de": {"der", "die", "und", "das", "ist", "nicht", "mit", "ein", "eine", "für", "auf", "sich", "wie"},
+	"it": {"il", "che", "di", "una", "per", "con", "non", "della", "sono", "come", "gli", "delle"},
+	"id": {"yang", "dan", "di", "ini", "dengan", "untuk", "dari", "pada", "adalah", "cara", "tidak", "bisa"},
+	"tr": {"ve", "bir", "bu", "için", "ile", "olan", "gibi", "daha", "nasıl", "açığı"},
+}
+
+// detectLanguage guesses an ISO 639-1 code from the script, then from
+// stopword frequency; it returns "" when unsure
+func detectLanguage(text string) string {
+	scripts := map[string]int{}
+	letters := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		switch {
+		case unicode.Is(unicode.Hiragana, r), unicode.Is(unicode.Katakana, r):
+			scripts["ja"]++
+		case unicode.Is(unicode.Hangul, r):
+			scripts["ko"]++
+		case unicode.Is(unicode.Han, r):
+			scripts["zh"]++
+		case unicode.Is(unicode.Cyrillic, r):
+			scripts["ru"]++
+		case unicode.Is(unicode.Arabic, r):
+			scripts["ar"]++
+		}
+	}
+	if letters == 0 {
+		return ""
+	}
+	// Japanese mixes kana with Han characters
+	if scripts["ja"] > 0 && scripts["ja"]+scripts["zh"] > letters/3 {
+		return "ja"
+	}
+	for _, code := range []string{"ko", "zh", "ru", "ar"} {
+		if scripts[code] > letters/3 {
+			return code
+		}
+	}
+
+	counts := map[string]int{}
+	for _, word := range splitWords(text) {
+		for code, stopwords := range languageStopwords {
+			for _, stopword := range stopwords {
+				if word == stopword {
+					counts[code]++
+					break
+				}
+			}
+		}
+	}
+
+	best, bestCount, tied := "", 0, false
+	for code, count := range counts {
+		switch {
+		case count > bestCount:
+			best, bestCount, tied = code, count, false
+		case count == bestCount:
+			tied = true
+		}
+	}
+	if bestCount < minLanguageHits || tied {
+		return ""
+	}
+	return best
+}
+
+// acceptsLanguage reports whether a profile wants articles in a language;
+// undetected languages are always accepted
+func (p Profile) acceptsLanguage(language string) bool {
+	if len(p.Languages) == 0 || language == "" {
+		return true
+	}
+	for _, allowed := range p.Languages {
+		if strings.EqualFold(allowed, language) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// lockFileName guards the state files against overlapping runs; the kernel
+// drops the lock when the process exits, so a crash never leaves it stale
+const lockFileName = "hunter.lock"
+
+// errLocked reports that another run holds the instance lock
+var errLocked = errors.New("another run holds the instance lock")
+
+// acquireLock takes the instance lock without waiting, returning a release
+// function, or an error naming the PID of the run that holds it
+func acquireLock() (func(), error) {
+	filename := stateFileName(lockFileName)
+	file, err := os.OpenFile(filename, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", filename, err)
+	}
+
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB); err != nil {
+		holder, _ := os.ReadFile(filename)
+		file.Close()
+		if errors.Is(err, syscall.EWOULDBLOCK) {
+			return nil, fmt.Errorf("%w (pid %s, %s); use --force to run anyway", errLocked, strings.TrimSpace(string(holder)), filename)
+		}
+		return nil, fmt.Errorf("locking %s: %w", filename, err)
+	}
+
+	file.Truncate(0)
+	file.WriteString(strconv.Itoa(os.Getpid()) + "\n")
+	return func() {
+		syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+		file.Close()
+	}, nil
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAcquireLock(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	release, err := acquireLock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := acquireLock(); !errors.Is(err, errLocked) {
+		t.Fatalf("second lock: got %v, want errLocked", err)
+	}
+
+	release()
+	release, err = acquireLock()
+	if err != nil {
+		t.Fatalf("lock after release: %v", err)
+	}
+	release()
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// lookbackLastRun limits matching to articles published since the previous
+// run recorded in lastTimeCheck.txt
+const lookbackLastRun = "last-run"
+
+// parseLookback accepts Go durations ("36h") and whole days ("7d")
+func parseLookback(value string) (time.Duration, error) {
+	if days, found := strings.CutSuffix(value, "d"); found {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid lookback %q", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid lookback %q", value)
+	}
+	return d, nil
+}
+
+// resolveCutoff turns a lookback spec into the oldest publish time still
+// notified. An empty spec or a missing last run falls back to the default.
+func resolveCutoff(spec string, now, lastRun, fallback time.Time) (time.Time, error) {
+	switch spec {
+	case "":
+		return fallback, nil
+	case lookbackLastRun:
+		if lastRun.IsZero() {
+			return fallback, nil
+		}
+		return lastRun, nil
+	}
+	d, err := parseLookback(spec)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return now.Add(-d), nil
+}
+
+// readLastCheckTime reads the time written by updateLastCheckTime; a missing
+// file returns the zero time
+func readLastCheckTime(filename string) (time.Time, error) {
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return time.Time{}, nil
+	}
+	if err != nil {
+		return time.Time{}, fmt.Errorf("reading %s: %w", filename, err)
+	}
+	text := strings.TrimSpace(string(data))
+	if text == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, text)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+	return t, nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseLookback(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "36h", want: 36 * time.Hour},
+		{value: "90m", want: 90 * time.Minute},
+		{value: "7d", want: 7 * 24 * time.Hour},
+		{value: "0d", want: 0},
+		{value: "-1d", wantErr: true},
+		{value: "-2h", wantErr: true},
+		{value: "1.5d", wantErr: true},
+		{value: "week", wantErr: true},
+		{value: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseLookback(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseLookback(%q) error = %v, want error %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseLookback(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestResolveCutoff(t *testing.T) {
+	now := time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+	lastRun := time.Date(2025, 1, 15, 6, 0, 0, 0, time.UTC)
+	fallback := now.Add(-24 * time.Hour)
+
+	tests := []struct {
+		spec    string
+		lastRun time.Time
+		want    time.Time
+		wantErr bool
+	}{
+		{spec: "", lastRun: lastRun, want: fallback},
+		{spec: lookbackLastRun, lastRun: lastRun, want: lastRun},
+		{spec: lookbackLastRun, want: fallback},
+		{spec: "36h", lastRun: lastRun, want: now.Add(-36 * time.Hour)},
+		{spec: "7d", want: now.AddDate(0, 0, -7)},
+		{spec: "soon", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := resolveCutoff(tt.spec, now, tt.lastRun, fallback)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("resolveCutoff(%q) error = %v, want error %v", tt.spec, err, tt.wantErr)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("resolveCutoff(%q) = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
@@ -3,8 +3,10 @@ package main
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"log"
@@ -14,10 +16,14 @@ import (
 	"net/http"
 	"net/url"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 	"syscall"
 	"time"
+	"unicode/utf8"
 
 	"github.com/fatih/color"
 	"github.com/joho/godotenv"
@@ -26,19 +32,40 @@ import (
 
 // Constants
 const (
-	maxRetries          = 3
-	retryBaseDelay      = time.Second
-	checkWindowDays     = -10
-	delayBetweenFeeds   = 10 * time.Second
-	configFileName      = ".env"
-	urlsFileName        = "data.txt"
-	foundUrlsFileName   = "found-url.txt"
-	lastCheckFileName   = "lastTimeCheck.txt"
-	telegramAPITemplate = "https://api.telegram.org/bot%s/sendMessage"
+	maxRetries        = 3
+	retryBaseDelay    = time.Second
+	delayBetweenFeeds = 10 * time.Second
+	configFileName    = ".env"
+	urlsFileName      = "data.txt"
+	foundUrlsFileName = "found-url.txt"
+	lastCheckFileName = "lastTimeCheck.txt"
+
+	// The end-of-run retry of a failed feed gets this many times its timeout
+	retryTimeoutFactor = 2
 )
 
 // Configuration
 var (
+	// environment namespaces state files and messages (e.g. "staging");
+	// empty or "prod" keeps the historical unprefixed behavior
+	environment = ""
+
+	// lookbackFlag overrides the lookback window for this run
+	lookbackFlag = ""
+
+	// profileName selects a single profile from hunter.yaml
+	profileName = ""
+
+	// dryRun prints notifications instead of sending them and leaves state files untouched
+	dryRun = false
+	// serveMode keeps running profiles on their schedules
+	serveMode = false
+	// summaryPath receives the JSON run summary, "-" meaning stdout
+	summaryPath = ""
+	// quiet prints only errors; verbose adds why articles were skipped
+	quiet   = false
+	verbose = false
+
 	keywords = map[string]string{
 		"general":                        "0",
 		"xss":                            "5",
@@ -102,138 +129,769 @@ var (
 )
 
 // TelegramMessage represents the structure of a message to be sent to Telegram
+// with sendMessage, or with sendPhoto when Photo is set
 type TelegramMessage struct {
-	ChatID          string `json:"chat_id"`
-	MessageThreadID string `json:"message_thread_id"`
-	Text            string `json:"text"`
+	ChatID             string                `json:"chat_id"`
+	MessageThreadID    string                `json:"message_thread_id,omitempty"`
+	Text               string                `json:"text,omitempty"`
+	Photo              string                `json:"photo,omitempty"`
+	Caption            string                `json:"caption,omitempty"`
+	ParseMode          string                `json:"parse_mode,omitempty"`
+	LinkPreviewOptions *LinkPreviewOptions   `json:"link_preview_options,omitempty"`
+	ReplyMarkup        *InlineKeyboardMarkup `json:"reply_markup,omitempty"`
+	// DisableNotification delivers the message without a sound
+	DisableNotification bool `json:"disable_notification,omitempty"`
+}
+
+// LinkPreviewOptions picks the link Telegram previews under a message
+type LinkPreviewOptions struct {
+	URL              string `json:"url,omitempty"`
+	PreferLargeMedia bool   `json:"prefer_large_media,omitempty"`
+}
+
+// Notification is a message for the channel, optionally illustrated with the
+// article's preview image or a large preview of its link
+type Notification struct {
+	Text       string
+	ImageURL   string
+	PreviewURL string
+	ParseMode  string
+	Buttons    *InlineKeyboardMarkup
+
+	// Article and Tag let a per-chat template replace Text
+	Article *Article
+	Tag     string
+	// Store is the article store the article is recorded in, so a queued
+	// message marks it delivered when it goes out
+	Store string
 }
 
 // Article represents a processed feed item
 type Article struct {
-	Title       string
-	Description string
-	Link        string
-	Published   string
-	Keywords    []string
+	Title          string
+	Description    string
+	Link           string
+	Published      string
+	Author         string
+	Keywords       []string
+	Video          bool          // from a YouTube source
+	Exploit        *ExploitInfo  // from an exploit archive source
+	PaidOnly       bool          // behind a Substack paywall
+	Score          float64       // sum of matched keyword weights
+	CVEs           []string      // CVE IDs mentioned in the text
+	Bounty         *Bounty       // largest payout mentioned, if any
+	Programs       []string      // bug bounty programs or vendors mentioned
+	Relevance      float64       // classifier confidence or embedding similarity
+	Severity       string        // heuristic severity label
+	FollowedAuthor string        // followed author the article was notified for
+	Summary        string        // readability excerpt of the page
+	Content        string        // readable page text, when readability is on
+	HasPoC         bool          // the fetched page includes a proof of concept
+	ArchiveURL     string        // Wayback Machine snapshot
+	WordCount      int           // words in the fetched page content
+	Language       string        // detected ISO 639-1 code, "" when unsure
+	ImageURL       string        // preview image (og:image or the feed's)
+	CVEDetails     []CVEDetail   // NVD details of the mentioned CVEs
+	Messages       []SentMessage // Telegram messages the article went out in
+}
+
+// RunConfig tunes retries and pacing of a run
+type RunConfig struct {
+	MaxRetries        int
+	BaseDelay         time.Duration
+	Jitter            time.Duration
+	MaxDelay          time.Duration
+	CheckWindowDays   int
+	DelayBetweenFeeds time.Duration
+	FeedTimeout       time.Duration // per feed, including retries
+	RunTimeout        time.Duration // whole run; zero means no deadline
+	FeedBudget        time.Duration // hard wall-clock limit per feed; zero means FeedTimeout plus a grace period
+}
+
+var defaultRunConfig = RunConfig{
+	MaxRetries:        3,
+	BaseDelay:         2 * time.Second,
+	Jitter:            1 * time.Second,
+	MaxDelay:          30 * time.Second,
+	CheckWindowDays:   -7, // Look back 7 days
+	DelayBetweenFeeds: 5 * time.Second,
+	FeedTimeout:       2 * time.Minute,
 }
 
-// init loads environment variables from .env file
+// init loads environment variables from the optional .env file
 func init() {
-	if err := godotenv.Load(configFileName); err != nil {
-		log.Fatalf("Error loading %s file: %v", configFileName, err)
+	if err := godotenv.Load(configFileName); err != nil && !errors.Is(err, os.ErrNotExist) {
+		fatalConfig("Error loading %s file: %v", configFileName, err)
 	}
 }
 
 func main() {
-	printHeader("Starting Writeup Finder Script", color.FgGreen)
+	flag.BoolVar(&dryRun, "dry-run", false, "fetch and match feeds but print notifications instead of sending them or saving state")
+	flag.StringVar(&lookbackFlag, "lookback", "", `how far back to notify, e.g. "7d", "36h" or "last-run" (since lastTimeCheck.txt)`)
+	flag.StringVar(&profileName, "profile", "", `run only the named profile from hunter.yaml ("all" or empty runs every profile)`)
+	flag.BoolVar(&quiet, "quiet", false, "print only errors, e.g. when run from cron")
+	flag.BoolVar(&verbose, "verbose", false, "also print why articles were skipped")
+	noColor := flag.Bool("no-color", false, "print without colors (NO_COLOR does the same)")
+	force := flag.Bool("force", false, "run even if another run holds the instance lock")
+	flag.StringVar(&summaryPath, "summary", "", `write a JSON run summary to this file ("-" for stdout)`)
+	flag.Parse()
+	if *noColor {
+		color.NoColor = true
+	}
+
+	environment = strings.ToLower(strings.TrimSpace(os.Getenv("HUNTER_ENV")))
+
+	// config validate reports broken settings itself instead of failing to load them
+	if flag.Arg(0) == "config" {
+		os.Exit(runConfig(flag.Args()[1:]))
+	}
+
+	if err := loadConfiguration(); err != nil {
+		fatalConfig("Error %v", err)
+	}
+
+	if err := configureProxy(); err != nil {
+		fatalConfig("Error configuring proxy: %v", err)
+	}
+
+	if err := configureHTTPClient(); err != nil {
+		fatalConfig("Error configuring HTTP client: %v", err)
+	}
+
+	if flag.NArg() > 0 {
+		args := flag.Args()
+		switch args[0] {
+		case "opml":
+			if err := runOPML(args[1:]); err != nil {
+				log.Fatalf("opml: %v", err)
+			}
+			return
+		case "backfill":
+			if err := runBackfill(args[1:]); err != nil {
+				log.Fatalf("backfill: %v", err)
+			}
+			return
+		case "stats":
+			if err := runStats(args[1:]); err != nil {
+				log.Fatalf("stats: %v", err)
+			}
+			return
+		case "embeddings":
+			if err := runEmbeddings(args[1:]); err != nil {
+				log.Fatalf("embeddings: %v", err)
+			}
+			return
+		case "cve":
+			if err := runCVE(args[1:]); err != nil {
+				log.Fatalf("cve: %v", err)
+			}
+			return
+		case "health":
+			if err := runHealth(args[1:]); err != nil {
+				log.Fatalf("health: %v", err)
+			}
+			return
+		case "feedback":
+			if err := runFeedback(args[1:]); err != nil {
+				log.Fatalf("feedback: %v", err)
+			}
+			return
+		case "claim":
+			if err := runClaim(args[1:]); err != nil {
+				log.Fatalf("claim: %v", err)
+			}
+			return
+		case "serve":
+			// Runs the profiles below on their schedules instead of once
+			serveMode = true
+		default:
+			fatalConfig("unknown command %q", args[0])
+		}
+	}
 
-	// Configuration
-	config := struct {
-		MaxRetries        int
-		BaseDelay         time.Duration
-		Jitter            time.Duration
-		MaxDelay          time.Duration
-		CheckWindowDays   int
-		DelayBetweenFeeds time.Duration
-	}{
-		MaxRetries:        3,
-		BaseDelay:         2 * time.Second,
-		Jitter:            1 * time.Second,
-		MaxDelay:          30 * time.Second,
-		CheckWindowDays:   -7, // Look back 7 days
-		DelayBetweenFeeds: 5 * time.Second,
+	printHeader("Starting Writeup Finder Script", color.FgGreen)
+	if dryRun {
+		printStatus("Dry run: nothing will be sent or saved", color.FgYellow)
 	}
 
 	// Validate environment variables
-	botToken := os.Getenv("TELEGRAM_BOT_TOKEN")
-	if botToken == "" {
-		log.Fatal("TELEGRAM_BOT_TOKEN environment variable not set")
+	if environment != "" && environment != "prod" {
+		printStatus(fmt.Sprintf("Running in %s environment", environment), color.FgYellow)
+	}
+
+	botToken := getEnvForEnvironment("TELEGRAM_BOT_TOKEN")
+	if botToken == "" && !dryRun {
+		fatalConfig("TELEGRAM_BOT_TOKEN environment variable not set")
+	}
+	channelID := getEnvForEnvironment("TELEGRAM_CHANNEL_ID")
+
+	profiles, err := runnableProfiles(channelID)
+	if err != nil {
+		fatalConfig("%v", err)
+	}
+	config := runConfigFromSettings()
+
+	// Overlapping runs would both send what found-url.txt does not list yet
+	release := func() {}
+	if !dryRun && !*force {
+		if release, err = acquireLock(); err != nil {
+			if errors.Is(err, errLocked) {
+				log.Print(err)
+				os.Exit(exitLocked)
+			}
+			fatalConfig("%v", err)
+		}
 	}
-	channelID := os.Getenv("TELEGRAM_CHANNEL_ID")
-	if channelID == "" {
-		log.Fatal("TELEGRAM_CHANNEL_ID environment variable not set")
+
+	if serveMode {
+		err := runServe(profiles, botToken, channelID, config)
+		release()
+		if err != nil {
+			fatalConfig("serve: %v", err)
+		}
+		return
 	}
+	code := runExitCode(runOnce(context.Background(), profiles, botToken, config))
+	release()
+	os.Exit(code)
+}
 
+// loadConfiguration reads hunter.yaml and resolves its keyword categories.
+// The globals are only replaced once both succeeded.
+func loadConfiguration() error {
+	loaded, err := loadSettings(settingsFileName)
+	if err != nil {
+		return fmt.Errorf("loading settings: %w", err)
+	}
+	terms, threads, disabled, err := resolveCategories(loaded.Categories)
+	if err != nil {
+		return fmt.Errorf("resolving keyword categories: %w", err)
+	}
+	settings = loaded
+	categoryOfTerm, categoryThreads, disabledTerms = terms, threads, disabled
+	return nil
+}
+
+// runnableProfiles selects the --profile profiles and checks each has a chat
+func runnableProfiles(channelID string) ([]Profile, error) {
+	profiles, err := selectProfiles(settings.Profiles, profileName, channelID)
+	if err != nil {
+		return nil, err
+	}
+	for _, profile := range profiles {
+		for _, destination := range profile.Destinations {
+			if destination.ChannelID == "" && !dryRun {
+				return nil, fmt.Errorf("no channel configured for profile %q: set TELEGRAM_CHANNEL_ID or channel_id", profile.Name)
+			}
+		}
+	}
+	return profiles, nil
+}
+
+// runConfigFromSettings applies hunter.yaml's timeouts and pacing to the defaults
+func runConfigFromSettings() RunConfig {
+	config := defaultRunConfig
+	if settings.FeedTimeout > 0 {
+		config.FeedTimeout = settings.FeedTimeout
+	}
+	if settings.RunTimeout > 0 {
+		config.RunTimeout = settings.RunTimeout
+	}
+	if settings.FeedBudget > 0 {
+		config.FeedBudget = settings.FeedBudget
+	}
+	if settings.FeedStagger > 0 {
+		config.DelayBetweenFeeds = settings.FeedStagger
+	}
+	return config
+}
+
+// runOnce runs the profiles one after another, between loading and saving
+// the state they share, and returns what the run did
+func runOnce(parent context.Context, profiles []Profile, botToken string, config RunConfig) RunSummary {
+	// The run deadline bounds every profile, feed and notification
+	ctx := parent
+	if config.RunTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.RunTimeout)
+		defer cancel()
+	}
+	resetTelegramRun()
+	started := time.Now()
+	feedsBefore := metricFeedsProcessed()
+	defer func() {
+		// A run that fetched at least one feed counts as successful
+		succeeded := metricFeedsProcessed() > feedsBefore
+		if succeeded {
+			metricRunSucceeded()
+		}
+		metricRunFinished(time.Since(started))
+		sendHeartbeat(context.WithoutCancel(ctx), succeeded)
+	}()
+
+	// Messages a previous run could not deliver go out first
+	var err error
+	outboxFile := stateFileName(outboxFileName)
+	if !dryRun {
+		if err = outbox.load(outboxFile); err != nil {
+			printError(fmt.Sprintf("Error reading outbox: %v", err))
+		}
+		flushOutbox(ctx, botToken)
+	}
+	topicsFile := stateFileName(topicsFileName)
+	if forumTopics.topics, err = readTopics(topicsFile); err != nil {
+		printError(fmt.Sprintf("Error reading forum topics: %v", err))
+		forumTopics.topics = make(map[string]map[string]string)
+	}
+
+	summary := RunSummary{Started: started.UTC()}
+	for _, profile := range profiles {
+		summary.Profiles = append(summary.Profiles, runProfile(ctx, profile, botToken, config))
+	}
+
+	if !dryRun {
+		failover(ctx, botToken)
+		if err := outbox.save(outboxFile); err != nil {
+			printError(fmt.Sprintf("Error saving outbox: %v", err))
+		}
+		if err := writeTopics(topicsFile, forumTopics.topics); err != nil {
+			printError(fmt.Sprintf("Error saving forum topics: %v", err))
+		}
+	}
+
+	summary.DurationSeconds = time.Since(started).Seconds()
+	if summaryPath != "" {
+		if err := writeRunSummary(summaryPath, summary); err != nil {
+			printError(fmt.Sprintf("Error writing run summary: %v", err))
+		}
+	}
+	return summary
+}
+
+// runProfile processes every feed of a profile and notifies its destinations
+func runProfile(ctx context.Context, profile Profile, botToken string, config RunConfig) ProfileSummary {
+	summary := ProfileSummary{Name: profile.Name}
+	if profile.Name != "" {
+		printHeader(fmt.Sprintf("Profile: %s", profile.Name), color.FgGreen)
+	}
 	// Initialize tracking
 	startTime := time.Now()
 	headermsg := fmt.Sprintf("Writeup Finder Started - %s", startTime.Format("2006-01-02 15:04:05"))
-	sendToTelegram(headermsg, botToken, channelID, keywords["general"])
+	profile.notify(ctx, botToken, "general", profile.label()+headermsg)
 
 	// Domain-specific rate limiter
 	rateLimiter := NewRateLimiter(5*time.Second, 2*time.Second)
+	backoffFile := profile.stateFile(backoffStateFileName)
+	if backoff, err := readBackoffState(backoffFile); err != nil {
+		printError(fmt.Sprintf("Error reading rate limit state: %v", err))
+	} else {
+		rateLimiter.RestoreBackoff(backoff)
+	}
 
 	// Load URLs
-	urls, err := readURLs(urlsFileName)
+	urls, err := readURLs(profile.Feeds)
 	if err != nil {
-		log.Fatalf("Error reading URLs: %v", err)
+		printError(fmt.Sprintf("Error reading URLs: %v", err))
+		summary.Error = err.Error()
+		return summary
+	}
+	urls = expandFeedList(urls)
+
+	// A fetch abandoned by the watchdog may still be writing the fetch state
+	feedStateMu.Lock()
+	validatorsFile := profile.stateFile(feedValidatorsFileName)
+	if feedValidators, err = readFeedValidators(validatorsFile); err != nil {
+		printError(fmt.Sprintf("Error reading feed validators: %v", err))
+		feedValidators = make(map[string]FeedValidators)
 	}
 
-	foundUrls, err := readFoundURLs(foundUrlsFileName)
+	githubWatchFile := profile.stateFile(githubWatchFileName)
+	if githubWatch, err = readGitHubWatch(githubWatchFile); err != nil {
+		printError(fmt.Sprintf("Error reading GitHub watch state: %v", err))
+		githubWatch = make(map[string][]string)
+	}
+
+	discoveredFile := stateFileName(discoveredFeedsFileName)
+	if discoveredFeeds, err = readDiscoveredFeeds(discoveredFile); err != nil {
+		printError(fmt.Sprintf("Error reading discovered feeds: %v", err))
+		discoveredFeeds = make(map[string]string)
+	}
+	feedStateMu.Unlock()
+
+	digestFile := profile.stateFile(digestFileName)
+	digest, err := readDigestState(digestFile)
+	if err != nil {
+		printError(fmt.Sprintf("Error reading digest state: %v", err))
+	}
+
+	circuitFile := profile.stateFile(circuitStateFileName)
+	circuitState, err := readCircuitState(circuitFile)
+	if err != nil {
+		printError(fmt.Sprintf("Error reading circuit breaker state: %v", err))
+		circuitState = make(map[string]time.Time)
+	}
+	breaker := newCircuitBreaker(settings.CircuitBreaker, circuitState)
+
+	healthFile := profile.stateFile(feedHealthFileName)
+	health, err := readFeedHealth(healthFile)
+	if err != nil {
+		printError(fmt.Sprintf("Error reading feed health: %v", err))
+		health = make(map[string]*FeedHealth)
+	}
+	var deadFeeds []string
+
+	foundUrlsFile := profile.stateFile(foundUrlsFileName)
+	foundUrls, err := readFoundURLs(foundUrlsFile)
 	if err != nil {
 		log.Printf("Warning: reading found URLs: %v", err)
 		foundUrls = make(map[string]struct{})
 	}
+	embeddedFile := profile.stateFile(embeddedFileName)
+	embedded, err := readFoundURLs(embeddedFile)
+	if err != nil {
+		printError(fmt.Sprintf("Error reading embedded URLs: %v", err))
+		embedded = make(map[string]struct{})
+	}
+	classifiedFile := profile.stateFile(classifiedFileName)
+	classified, err := readFoundURLs(classifiedFile)
+	if err != nil {
+		printError(fmt.Sprintf("Error reading classified URLs: %v", err))
+		classified = make(map[string]struct{})
+	}
 
-	cutoffTime := time.Now().AddDate(0, 0, config.CheckWindowDays)
+	// Lookback: --lookback, then hunter.yaml, then the built-in window
+	lastRun, err := readLastCheckTime(profile.stateFile(lastCheckFileName))
+	if err != nil {
+		printError(fmt.Sprintf("Error reading last check time: %v", err))
+	}
+	runStart := time.Now()
+	defaultCutoff := runStart.AddDate(0, 0, config.CheckWindowDays)
+	lookback := lookbackFlag
+	if lookback == "" {
+		lookback = settings.Lookback
+	}
+	runCutoff, err := resolveCutoff(lookback, runStart, lastRun, defaultCutoff)
+	if err != nil {
+		printError(fmt.Sprintf("Error resolving lookback, using %d days: %v", -config.CheckWindowDays, err))
+		runCutoff = defaultCutoff
+	}
 	articlesFound := 0
 	failedFeeds := 0
+	var matched []*Article
+	var latencies []time.Duration
+	var timings []FeedTiming
+
+	// Process feeds; SIGHUP re-reads the feed list and queues new entries.
+	// Feeds that fail to fetch get one more try at the end, with a longer
+	// timeout, since many failures are transient.
+	reload := watchReload()
+	defer signal.Stop(reload)
+	var retryFeeds []string
+	retryStart := 0
+	for i := 0; ; i++ {
+		if i == len(urls) {
+			if retryStart > 0 || len(retryFeeds) == 0 {
+				break
+			}
+			retryStart = i
+			urls = append(urls, retryFeeds...)
+			printStatus(fmt.Sprintf("Retrying %d failed feeds", len(retryFeeds)), color.FgYellow)
+		}
+		retrying := retryStart > 0
+		// Validators of a feed that failed before being processed are dropped
+		discardFeedValidators()
+
+		if !retrying {
+			select {
+			case <-reload:
+				urls = reloadFeedList(profile.Feeds, urls)
+			default:
+			}
+		}
+
+		if ctx.Err() != nil {
+			printError(fmt.Sprintf("Run deadline reached, skipping %d remaining feeds", len(urls)-i))
+			// Feeds awaiting their retry were counted when they failed
+			if !retrying {
+				failedFeeds += len(urls) - i
+			}
+			break
+		}
 
-	// Process feeds
-	for i, url := range urls {
-		printStatus(fmt.Sprintf("Processing feed %d/%d: %s", i+1, len(urls), url), color.FgMagenta)
+		url := urls[i]
+		if retrying {
+			printStatus(fmt.Sprintf("Retrying feed %d/%d: %s", i-retryStart+1, len(retryFeeds), url), color.FgMagenta)
+		} else {
+			printStatus(fmt.Sprintf("Processing feed %d/%d: %s", i+1, len(urls), url), color.FgMagenta)
+		}
+		if quarantined(health, url) {
+			printStatus("Skipping quarantined feed (see the health command)", color.FgYellow)
+			continue
+		}
 
 		// Respect domain rate limits
 		domain := getDomain(url)
+		if breaker.Open(domain, time.Now()) {
+			printStatus(fmt.Sprintf("Skipping %s: circuit open after repeated failures", domain), color.FgYellow)
+			if !retrying {
+				failedFeeds++
+				summary.failed(url, fmt.Errorf("circuit open for %s", domain))
+			}
+			continue
+		}
 		rateLimiter.Wait(domain)
 
 		// Fetch with retry and backoff
-		articles, err := fetchArticlesWithRetry(url, config.MaxRetries, config.BaseDelay, config.Jitter, config.MaxDelay)
+		feedTimeout := config.FeedTimeout
+		if timeout := feedOptions(url).Timeout; timeout > 0 {
+			feedTimeout = timeout
+		}
+		budget := config.FeedBudget
+		if budget <= 0 {
+			budget = feedTimeout + watchdogGrace
+		}
+		if retrying {
+			feedTimeout *= retryTimeoutFactor
+			budget *= retryTimeoutFactor
+		}
+		feedCtx, cancelFeed := context.WithTimeout(ctx, feedTimeout)
+		fetchStart := time.Now()
+		articles, err := withWatchdog(feedCtx, budget, func(ctx context.Context) ([]*gofeed.Item, error) {
+			return fetchArticlesWithRetry(ctx, url, rateLimiter, config.MaxRetries, config.BaseDelay, config.Jitter, config.MaxDelay)
+		})
+		cancelFeed()
+		timings = append(timings, FeedTiming{URL: url, Duration: time.Since(fetchStart), Failed: err != nil && !errors.Is(err, errNotModified)})
+		if retrying && (err == nil || errors.Is(err, errNotModified)) {
+			failedFeeds--
+			summary.recovered(url)
+		}
+		if err == nil || errors.Is(err, errNotModified) {
+			summary.FeedsProcessed++
+		}
+		if errors.Is(err, errNotModified) {
+			breaker.Success(domain)
+			recordFeedSuccess(health, url, time.Now())
+			printStatus("Feed not modified since last run", color.FgYellow)
+			continue
+		}
 		if err != nil {
 			printError(fmt.Sprintf("Error fetching feed from %s: %v", url, err))
+			metricFetchError(domain)
+			// A failed retry was already counted and recorded the first time
+			if retrying {
+				continue
+			}
 			failedFeeds++
+			summary.failed(url, err)
+			if breaker.Failure(domain, time.Now()) {
+				printError(fmt.Sprintf("Circuit opened for %s, skipping its remaining feeds", domain))
+			}
+			if recordFeedFailure(health, url, err, time.Now()) {
+				deadFeeds = append(deadFeeds, url)
+			} else {
+				retryFeeds = append(retryFeeds, url)
+			}
 			continue
 		}
+		breaker.Success(domain)
+		recordFeedSuccess(health, url, time.Now())
+		metricFeedProcessed()
+
+		if feedOptions(url).Type == feedTypeAdvisory {
+			message, err := processAdvisoryFeed(profile.stateFile(advisoryStateFileName), url, articles)
+			if err != nil {
+				printError(fmt.Sprintf("Error diffing advisories from %s: %v", url, err))
+			} else {
+				if message != "" {
+					profile.notify(ctx, botToken, "advisories", message)
+					printSuccess(message)
+				}
+				commitFeedValidators()
+			}
+			continue
+		}
+
+		dedup, err := dedupStrategyFor(url)
+		if err != nil {
+			printError(fmt.Sprintf("Error selecting dedup strategy for %s: %v", url, err))
+			failedFeeds++
+			summary.failed(url, err)
+			continue
+		}
+
+		cutoffTime := runCutoff
+		if spec := feedOptions(url).Lookback; spec != "" {
+			if cutoffTime, err = resolveCutoff(spec, runStart, lastRun, runCutoff); err != nil {
+				printError(fmt.Sprintf("Error resolving lookback for %s: %v", url, err))
+				cutoffTime = runCutoff
+			}
+		}
+
+		withinLookback := func(item *gofeed.Item) bool {
+			pubDate, err := parseDate(item.Published)
+			if err != nil {
+				printError(fmt.Sprintf("Error parsing date for %s: %v", item.Link, err))
+				return false
+			}
+			if pubDate.Before(cutoffTime) {
+				printVerbose(fmt.Sprintf("Skipping %s: published %s, before the lookback", item.Title, pubDate.Format("2006-01-02")))
+				return false
+			}
+			return true
+		}
 
-		// Process articles
+		// Process articles; notifications wait for the end of the feed so a
+		// burst of matches can be grouped
 		newArticles := 0
+		var pending []pendingNotification
+		var processed []processedArticle
 		for _, item := range articles {
-			if _, exists := foundUrls[item.Link]; exists {
+			if isDuplicate(dedup, item, foundUrls) {
+				printVerbose(fmt.Sprintf("Skipping already seen %s", item.Link))
 				continue
 			}
+			dedupKey := dedup.Key(item)
 
-			article := processArticle(item)
-			if article == nil {
+			// Items the feed dates are filtered before any page is read;
+			// undated ones once their page has given a date
+			dated := item.Published != ""
+			if dated && !withinLookback(item) {
 				continue
 			}
 
-			pubDate, err := parseDate(item.Published)
+			// Sitemap and rendered entries only carry a link until their page is read
+			if feedOptions(url).Type == feedTypeSitemap || usesBrowser(url) {
+				if err := fillFromPage(ctx, item); err != nil {
+					printError(fmt.Sprintf("Error reading page %s: %v", item.Link, err))
+					continue
+				}
+			}
+
+			extracted, err := extractContent(ctx, item.Link, fullBodyFor(url))
 			if err != nil {
-				printError(fmt.Sprintf("Error parsing date for %s: %v", item.Link, err))
+				printError(fmt.Sprintf("Error extracting content from %s: %v", item.Link, err))
+			}
+			if extracted != nil && item.Published == "" {
+				item.Published = extracted.Date
+			}
+			if !dated && !withinLookback(item) {
 				continue
 			}
 
-			if pubDate.Before(cutoffTime) {
+			article := processArticle(item, extracted, profile.Keywords)
+			if _, scored := embedded[dedupKey]; article == nil && embeddingsEnabled() && !scored {
+				article, err = embeddingMatch(ctx, item, extracted, profile.Keywords)
+				if err != nil {
+					printError(fmt.Sprintf("Error embedding %s: %v", item.Link, err))
+				} else if article == nil && !dryRun {
+					embedded[dedupKey] = struct{}{}
+					if err := saveURL(dedupKey, embeddedFile); err != nil {
+						printError(fmt.Sprintf("Error saving embedded URL: %v", err))
+					}
+				}
+			}
+			if _, judged := classified[dedupKey]; article == nil && classifierEnabled() && !judged {
+				article, err = classifyArticle(ctx, item, extracted, profile.Keywords)
+				if err != nil {
+					printError(fmt.Sprintf("Error classifying %s: %v", item.Link, err))
+				} else if article == nil && !dryRun {
+					// A rejection stands; the item is not classified again
+					classified[dedupKey] = struct{}{}
+					if err := saveURL(dedupKey, classifiedFile); err != nil {
+						printError(fmt.Sprintf("Error saving classified URL: %v", err))
+					}
+				}
+			}
+			if article == nil {
+				printVerbose(fmt.Sprintf("No match: %s", item.Title))
+				continue
+			}
+			if !profile.acceptsLanguage(article.Language) {
+				printVerbose(fmt.Sprintf("Skipping %s: language %q not wanted", item.Title, article.Language))
 				continue
 			}
 
+			if settings.NVD.Enrich && len(article.CVEs) > 0 {
+				article.CVEDetails = enrichCVEs(ctx, article.CVEs)
+			}
+			if settings.Wayback.Enabled && !dryRun {
+				if article.ArchiveURL, err = archiveToWayback(ctx, article.Link); err != nil {
+					printError(fmt.Sprintf("Error archiving %s: %v", article.Link, err))
+				}
+			}
+
+			// Queue under a tag until the feed is done, or hold for the digest
+			deliver := func(tag string) {
+				if digestEnabled() {
+					digest.Entries = append(digest.Entries, DigestEntry{Tag: tag, Title: article.Title, Link: article.Link})
+					return
+				}
+				pending = append(pending, pendingNotification{Tag: tag, Article: article})
+			}
+
 			// Send notifications for each keyword
 			for _, keyword := range article.Keywords {
-				message := formatTelegramMessage(article, keyword)
-				sendToTelegram(message, botToken, channelID, keywords[keyword])
-				printSuccess(message)
+				deliver(keyword)
+				metricArticleMatched(keyword)
+				summary.matched(keyword)
 				articlesFound++
 				newArticles++
 			}
+			// Programs with their own thread get a copy there
+			for _, program := range article.Programs {
+				if programThread(program) != "" {
+					deliver(program)
+				}
+			}
+			if severityThread(article.Severity) != "" {
+				deliver(article.Severity)
+			}
 
-			// Mark as processed
-			if err := saveURL(item.Link, foundUrlsFileName); err != nil {
-				printError(fmt.Sprintf("Error saving URL: %v", err))
+			if dryRun {
+				foundUrls[dedupKey] = struct{}{}
 				continue
 			}
-			foundUrls[item.Link] = struct{}{}
+
+			pocText := item.Description + " " + item.Content
+			if extracted != nil {
+				pocText += " " + extracted.Body + " " + strings.Join(extracted.PoCs, " ")
+			}
+			mirrorPoCs(ctx, pocText)
+			if settings.Snapshot.Dir != "" {
+				if path, err := saveSnapshot(ctx, article); err != nil {
+					printError(fmt.Sprintf("Error saving snapshot of %s: %v", article.Link, err))
+				} else {
+					printStatus(fmt.Sprintf("Saved snapshot %s", path), color.FgBlue)
+				}
+			}
+			// Later items of this feed are checked against it right away
+			foundUrls[dedupKey] = struct{}{}
+			processed = append(processed, processedArticle{article: article, dedupKey: dedupKey})
 		}
 
+		sendFeedNotifications(ctx, profile, botToken, url, pending)
+
+		// Stored and marked once sent, so records carry their message IDs.
+		// Articles still queued, held or waiting for the digest are marked
+		// notified when their message goes out.
+		for _, p := range processed {
+			matched = append(matched, p.article)
+			record := newStoredArticle(p.article, url, len(p.article.Messages) > 0)
+			if record.LatencySeconds > 0 {
+				latencies = append(latencies, time.Duration(record.LatencySeconds*float64(time.Second)))
+				metricDeliveryLatency(url, record.LatencySeconds)
+			}
+			if err := appendStoredArticles(profile.stateFile(articleStoreFileName), record); err != nil {
+				printError(fmt.Sprintf("Error storing article: %v", err))
+			}
+
+			// Mark as processed
+			if err := saveURL(p.dedupKey, foundUrlsFile); err != nil {
+				printError(fmt.Sprintf("Error saving URL: %v", err))
+			}
+		}
 		printStatus(fmt.Sprintf("Found %d new articles in this feed", newArticles), color.FgYellow)
+		commitFeedValidators()
 
 		// Delay between feeds, but not after the last one
 		if i < len(urls)-1 {
@@ -242,32 +900,112 @@ func main() {
 	}
 
 	// Final report
+	feedCount := len(urls)
+	if retryStart > 0 {
+		feedCount = retryStart
+	}
 	duration := time.Since(startTime).Round(time.Second)
 	finishedMsg := fmt.Sprintf("Completed in %s. Total new articles found: %d. Failed feeds: %d/%d",
-		duration, articlesFound, failedFeeds, len(urls))
+		duration, articlesFound, failedFeeds, feedCount)
+	summary.Feeds = feedCount
+	summary.FailedFeeds = failedFeeds
+	summary.Articles = articlesFound
+	summary.DurationSeconds = time.Since(startTime).Seconds()
 
 	printStatus(finishedMsg, color.FgCyan)
+	if len(latencies) > 0 {
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		printStatus(fmt.Sprintf("Delivery latency: p50 %s, p95 %s", formatLatency(percentile(latencies, 50)),
+			formatLatency(percentile(latencies, 95))), color.FgCyan)
+	}
+	top := settings.SlowFeeds.Top
+	if top <= 0 {
+		top = defaultSlowFeedTop
+	}
+	var slowFeedsMsg string
+	if len(timings) > 0 {
+		slowFeedsMsg = formatSlowFeeds(timings, top)
+		printStatus(slowFeedsMsg, color.FgCyan)
+	}
 	printHeader("Writeup Hunter Script Completed", color.FgGreen)
-	sendToTelegram(finishedMsg, botToken, channelID, keywords["general"])
+	// The report still goes out when the run deadline cut the loop short
+	reportCtx, cancelReport := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+	defer cancelReport()
+	if digestEnabled() {
+		sendDigest(reportCtx, profile, botToken, &digest, time.Now())
+	}
+	profile.notify(reportCtx, botToken, "general", profile.label()+finishedMsg)
+	if settings.SlowFeeds.Report && slowFeedsMsg != "" {
+		profile.notify(reportCtx, botToken, slowFeedsTag, profile.label()+slowFeedsMsg)
+	}
+	if len(deadFeeds) > 0 {
+		deadMsg := fmt.Sprintf("Quarantined %d dead feeds:\n%s", len(deadFeeds), strings.Join(deadFeeds, "\n"))
+		printError(deadMsg)
+		profile.notify(reportCtx, botToken, "general", profile.label()+deadMsg)
+	}
 
-	if err := updateLastCheckTime(lastCheckFileName); err != nil {
+	if dryRun {
+		return summary
+	}
+	feedStateMu.Lock()
+	if err := writeDiscoveredFeeds(discoveredFile, discoveredFeeds); err != nil {
+		printError(fmt.Sprintf("Error saving discovered feeds: %v", err))
+	}
+	if err := writeFeedValidators(validatorsFile, feedValidators); err != nil {
+		printError(fmt.Sprintf("Error saving feed validators: %v", err))
+	}
+	if err := writeGitHubWatch(githubWatchFile, githubWatch); err != nil {
+		printError(fmt.Sprintf("Error saving GitHub watch state: %v", err))
+	}
+	feedStateMu.Unlock()
+	if err := writeDigestState(digestFile, digest); err != nil {
+		printError(fmt.Sprintf("Error saving digest state: %v", err))
+	}
+	if err := writeFeedHealth(healthFile, health); err != nil {
+		printError(fmt.Sprintf("Error saving feed health: %v", err))
+	}
+	if err := writeBackoffState(backoffFile, rateLimiter.BackoffState(time.Now())); err != nil {
+		printError(fmt.Sprintf("Error saving rate limit state: %v", err))
+	}
+	if err := writeCircuitState(circuitFile, breaker, time.Now()); err != nil {
+		printError(fmt.Sprintf("Error saving circuit breaker state: %v", err))
+	}
+	if err := publishArticles(settings.Publish, matched); err != nil {
+		printError(fmt.Sprintf("Error publishing index: %v", err))
+	}
+	if err := updateLastCheckTime(profile.stateFile(lastCheckFileName)); err != nil {
 		printError(fmt.Sprintf("Error updating last check time: %v", err))
 	}
+	return summary
 }
 
 // NewRateLimiter creates a domain-based rate limiter
 type RateLimiter struct {
-	mu       sync.Mutex
-	lastReq  map[string]time.Time
-	minDelay time.Duration
-	jitter   time.Duration
+	mu           sync.Mutex
+	lastReq      map[string]time.Time
+	blockedUntil map[string]time.Time
+	backoff      map[string]DomainBackoff
+	minDelay     time.Duration
+	jitter       time.Duration
 }
 
 func NewRateLimiter(minDelay, jitter time.Duration) *RateLimiter {
 	return &RateLimiter{
-		lastReq:  make(map[string]time.Time),
-		minDelay: minDelay,
-		jitter:   jitter,
+		lastReq:      make(map[string]time.Time),
+		blockedUntil: make(map[string]time.Time),
+		backoff:      make(map[string]DomainBackoff),
+		minDelay:     minDelay,
+		jitter:       jitter,
+	}
+}
+
+// Penalize holds back further requests to a domain, e.g. for a Retry-After
+func (r *RateLimiter) Penalize(domain string, d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(r.blockedUntil[domain]) {
+		r.blockedUntil[domain] = until
 	}
 }
 
@@ -275,27 +1013,51 @@ func (r *RateLimiter) Wait(domain string) {
 	r.mu.Lock()
 	defer r.mu.Unlock()
 
+	if until, exists := r.blockedUntil[domain]; exists {
+		if wait := time.Until(until); wait > 0 {
+			time.Sleep(wait)
+		}
+		delete(r.blockedUntil, domain)
+	}
+
 	if last, exists := r.lastReq[domain]; exists {
 		elapsed := time.Since(last)
-		if elapsed < r.minDelay {
-			waitTime := r.minDelay - elapsed + time.Duration(rand.Int63n(int64(r.jitter)))
+		if minDelay := r.delayFor(domain); elapsed < minDelay {
+			waitTime := minDelay - elapsed + time.Duration(rand.Int63n(int64(r.jitter)))
 			time.Sleep(waitTime)
 		}
 	}
 	r.lastReq[domain] = time.Now()
 }
 
-// fetchArticlesWithRetry implements exponential backoff
-func fetchArticlesWithRetry(url string, maxRetries int, baseDelay, jitter, maxDelay time.Duration) (articles []*gofeed.Item, err error) {
+// fetchArticlesWithRetry implements exponential backoff, replaced by the
+// server's Retry-After when it sends one, which is also recorded on the limiter
+func fetchArticlesWithRetry(ctx context.Context, url string, limiter *RateLimiter, maxRetries int, baseDelay, jitter, maxDelay time.Duration) (articles []*gofeed.Item, err error) {
 	for attempt := 0; attempt <= maxRetries; attempt++ {
-		articles, err = fetchArticles(url)
+		articles, err = fetchArticles(ctx, url)
 		if err == nil {
 			return articles, nil
 		}
 
+		if errors.Is(err, errNotModified) {
+			return nil, err
+		}
+
 		if shouldRetry(err) {
 			delay := getBackoffDelay(attempt, baseDelay, jitter, maxDelay)
-			time.Sleep(delay)
+			var httpErr *HTTPError
+			if errors.As(err, &httpErr) && isRateLimited(httpErr.StatusCode) {
+				limiter.Throttle(getDomain(url))
+				if httpErr.RetryAfter > 0 {
+					delay = httpErr.RetryAfter
+					limiter.Penalize(getDomain(url), delay)
+				}
+			}
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return nil, fmt.Errorf("after %d attempts: %w", attempt+1, ctx.Err())
+			}
 			continue
 		}
 		break
@@ -303,6 +1065,11 @@ func fetchArticlesWithRetry(url string, maxRetries int, baseDelay, jitter, maxDe
 	return nil, fmt.Errorf("after %d attempts: %w", maxRetries, err)
 }
 
+// isRateLimited reports whether a status asks the client to slow down
+func isRateLimited(statusCode int) bool {
+	return statusCode == http.StatusTooManyRequests || statusCode == http.StatusServiceUnavailable
+}
+
 func shouldRetry(err error) bool {
 	if err == nil {
 		return false
@@ -363,6 +1130,7 @@ func shouldRetry(err error) bool {
 type HTTPError struct {
 	StatusCode int
 	Body       []byte
+	RetryAfter time.Duration // how long a 429/503 answer asked to wait
 }
 
 func (e *HTTPError) Error() string {
@@ -389,7 +1157,38 @@ func getDomain(urlStr string) string {
 
 // Helper functions
 
+// stateFileName namespaces a state file by environment, e.g. found-url.staging.txt
+func stateFileName(name string) string {
+	if environment == "" || environment == "prod" {
+		return name
+	}
+	ext := filepath.Ext(name)
+	return strings.TrimSuffix(name, ext) + "." + environment + ext
+}
+
+// getEnvForEnvironment prefers an environment-specific variable such as
+// TELEGRAM_CHANNEL_ID_STAGING so staging runs can target their own channel
+func getEnvForEnvironment(key string) string {
+	if environment != "" && environment != "prod" {
+		if value := lookupSecret(key + "_" + strings.ToUpper(environment)); value != "" {
+			return value
+		}
+	}
+	return lookupSecret(key)
+}
+
+// environmentPrefix tags outgoing messages from non-production runs
+func environmentPrefix() string {
+	if environment == "" || environment == "prod" {
+		return ""
+	}
+	return "[" + environment + "] "
+}
+
 func printHeader(message string, colorAttr color.Attribute) {
+	if quiet {
+		return
+	}
 	colored := color.New(colorAttr).SprintFunc()
 	fmt.Println(colored(strings.Repeat("=", 80)))
 	fmt.Println(colored(fmt.Sprintf("%80s", message)))
@@ -397,6 +1196,9 @@ func printHeader(message string, colorAttr color.Attribute) {
 }
 
 func printStatus(message string, colorAttr color.Attribute) {
+	if quiet {
+		return
+	}
 	timestamp := time.Now().Format("2006-01-02 15:04:05")
 	colored := color.New(colorAttr).SprintFunc()
 	fmt.Println(color.CyanString(timestamp), "-", colored(message))
@@ -406,7 +1208,17 @@ func printError(message string) {
 	fmt.Println(color.RedString("ERROR: %s", message))
 }
 
+// printVerbose reports details only --verbose asks for
+func printVerbose(message string) {
+	if verbose && !quiet {
+		printStatus(message, color.FgWhite)
+	}
+}
+
 func printSuccess(message string) {
+	if quiet {
+		return
+	}
 	fmt.Println(color.GreenString(message))
 	fmt.Println()
 }
@@ -421,7 +1233,7 @@ func readURLs(filename string) ([]string, error) {
 	var urls []string
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
-		if url := strings.TrimSpace(scanner.Text()); url != "" {
+		if url := strings.TrimSpace(scanner.Text()); url != "" && !strings.HasPrefix(url, "#") {
 			urls = append(urls, url)
 		}
 	}
@@ -447,7 +1259,12 @@ func readFoundURLs(filename string) (map[string]struct{}, error) {
 
 	scanner := bufio.NewScanner(file)
 	for scanner.Scan() {
-		foundUrls[scanner.Text()] = struct{}{}
+		link := scanner.Text()
+		foundUrls[link] = struct{}{}
+		// Medium links saved before the ?source= tracking parameter was stripped
+		if stripped := stripMediumSource(link); stripped != link {
+			foundUrls[stripped] = struct{}{}
+		}
 	}
 
 	if err := scanner.Err(); err != nil {
@@ -486,93 +1303,130 @@ func updateLastCheckTime(filename string) error {
 	return nil
 }
 
-func fetchArticles(feedURL string) ([]*gofeed.Item, error) {
-	fp := gofeed.NewParser()
+func fetchArticles(ctx context.Context, feedURL string) ([]*gofeed.Item, error) {
+	if usesBrowser(feedURL) {
+		return fetchRenderedArticles(ctx, feedURL)
+	}
 
-	// Check if it's our specific JSON feed
-	if strings.Contains(feedURL, "writeups.xyz/index.json") {
-		return parseWriteupsXYZFeed(feedURL)
+	switch feedOptions(feedURL).Type {
+	case feedTypeSitemap:
+		return fetchSitemap(ctx, feedURL)
+	}
+	if isNVDSource(feedURL) {
+		return fetchNVD(ctx, feedURL)
+	}
+	if isIMAPSource(feedURL) {
+		return fetchIMAP(ctx, feedURL)
+	}
+	if isPluginSource(feedURL) {
+		return runPlugin(ctx, feedURL)
 	}
 
-	// Handle regular RSS/Atom feeds
-	feed, err := fp.ParseURL(feedURL)
-	if err != nil {
-		return nil, fmt.Errorf("parsing feed URL: %w", err)
+	// Blog homepages listed in data.txt are read through their discovered feed
+	feedStateMu.Lock()
+	discovered, exists := discoveredFeeds[feedURL]
+	feedStateMu.Unlock()
+	if exists {
+		feedURL = discovered
 	}
-	return feed.Items, nil
-}
 
-func parseWriteupsXYZFeed(feedURL string) ([]*gofeed.Item, error) {
-	resp, err := http.Get(feedURL)
+	body, err := fetchFeedBody(ctx, feedURL)
 	if err != nil {
-		return nil, fmt.Errorf("fetching JSON feed: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	if isRedditListing(feedURL) {
+		return parseRedditListing(body)
 	}
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("reading response body: %w", err)
+	if isHackerNewsSearch(feedURL) {
+		return parseHackerNewsResults(body)
+	}
+	if isTwitterAPI(feedURL) {
+		return parseTwitterAPIResponse(body)
+	}
+	if isPentesterLandFeed(feedURL) {
+		return parsePentesterLandFeed(body)
+	}
+	if isGitHubWatch(feedURL) {
+		return parseGitHubWatch(ctx, body, feedURL, time.Now())
+	}
+	if isTelegramChannelSource(feedURL) {
+		return parseTelegramChannel(body)
 	}
 
-	// Define a struct that matches the JSON structure
-	type jsonItem struct {
-		Title         string `json:"title"`
-		Description   string `json:"description"`
-		Link          string `json:"link"`
-		PublishedDate string `json:"published"`
-		Authors       []struct {
-			Name string `json:"name"`
-		} `json:"authors"`
-		Vulnerabilities []struct {
-			Title string `json:"title"`
-		} `json:"vulnerabilities"`
+	if options := feedOptions(feedURL); options.Type == feedTypeJSON {
+		return parseJSONFeed(body, options.JSON)
 	}
 
-	var items []jsonItem
-	if err := json.Unmarshal(body, &items); err != nil {
-		return nil, fmt.Errorf("unmarshaling JSON: %w", err)
+	// Check if it's our specific JSON feed, or another hunter's published index
+	if strings.Contains(feedURL, "writeups.xyz/index.json") || strings.HasSuffix(feedURL, "/index.json") {
+		return parseJSONFeed(body, writeupsXYZMapping)
 	}
 
-	// Convert to gofeed.Items
-	var feedItems []*gofeed.Item
-	for _, item := range items {
-		// Format authors
-		var authors []string
-		for _, author := range item.Authors {
-			authors = append(authors, author.Name)
+	// Handle regular RSS/Atom feeds
+	fp := gofeed.NewParser()
+	feed, err := fp.Parse(bytes.NewReader(body))
+	if err == nil {
+		if isMediumFeed(feedURL, feed) {
+			normalizeMediumLinks(feed.Items)
 		}
-
-		// Format vulnerabilities/tags
-		var tags []string
-		for _, vuln := range item.Vulnerabilities {
-			tags = append(tags, vuln.Title)
+		if isNitterFeed(feedURL) {
+			return extractTweetLinks(feed.Items), nil
 		}
-
-		feedItem := &gofeed.Item{
-			Title:       item.Title,
-			Description: item.Description,
-			Link:        item.Link,
-			Published:   item.PublishedDate,
-			// Custom fields can be added to the Extensions map if needed
+		if isYouTubeFeed(feedURL) {
+			markVideos(feed.Items)
+		}
+		if isExploitArchive(feedURL) {
+			classifyExploits(feed.Items)
 		}
+		if isGoogleAlertsFeed(feedURL) {
+			unwrapGoogleAlerts(feed.Items)
+		}
+		if isSubstackFeed(feedURL, feed) {
+			prepareSubstackItems(feed.Items)
+		}
+		return feed.Items, nil
+	}
 
-		// // If you need to preserve the authors and tags, you could add them to a custom field
-		// if len(authors) > 0 {
-		// 	if feedItem.Extensions == nil {
-		// 		feedItem.Extensions = make(map[string]map[string][]gofeed.Extension)
-		// 	}
-		// 	feedItem.Extensions["custom"] = map[string][]gofeed.Extension{
-		// 		"authors": {gofeed.Extension{Value: strings.Join(authors, ", ")}},
-		// 	}
+	// Not a feed: look for an advertised one on the page
+	discovered, found := discoverFeedURL(body, feedURL)
+	if !found || discovered == feedURL {
+		return nil, fmt.Errorf("parsing feed: %w", err)
+	}
+	printStatus(fmt.Sprintf("Discovered feed %s on %s", discovered, feedURL), color.FgBlue)
 
-		feedItems = append(feedItems, feedItem)
-		// }
+	body, err = fetchFeedBody(ctx, discovered)
+	if err != nil {
+		return nil, err
+	}
+	feed, err = fp.Parse(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("parsing discovered feed %s: %w", discovered, err)
+	}
+	feedStateMu.Lock()
+	if ctx.Err() == nil {
+		discoveredFeeds[feedURL] = discovered
 	}
-	return feedItems, nil
+	feedStateMu.Unlock()
+	return feed.Items, nil
+}
+
+// writeupsXYZItem matches the writeups.xyz index.json structure
+type writeupsXYZItem struct {
+	Title           string              `json:"title"`
+	Description     string              `json:"description"`
+	Link            string              `json:"link"`
+	PublishedDate   string              `json:"published"`
+	Authors         []writeupsXYZAuthor `json:"authors"`
+	Vulnerabilities []writeupsXYZTag    `json:"vulnerabilities"`
+}
+
+type writeupsXYZAuthor struct {
+	Name string `json:"name"`
+}
+
+type writeupsXYZTag struct {
+	Title string `json:"title"`
 }
 
 // func fetchArticlesWithRetry(feedURL string, maxRetries int) ([]*gofeed.Item, error) {
@@ -606,6 +1460,12 @@ func parseDate(dateStr string) (time.Time, error) {
 		"2006-01-02 15:04:05 -0700",
 		"2006-01-02 15:04:05",
 		"02 Jan 2006 15:04:05 MST",
+		// W3C datetime forms used by sitemaps; RFC3339 above covers seconds
+		// with and without a fraction
+		"2006-01-02T15:04Z07:00",
+		"2006-01-02",
+		"2006-01",
+		"2006",
 	}
 
 	for _, format := range formats {
@@ -618,38 +1478,220 @@ func parseDate(dateStr string) (time.Time, error) {
 	return time.Time{}, fmt.Errorf("unable to parse date: %s", dateStr)
 }
 
-func processArticle(item *gofeed.Item) *Article {
-	articleText := strings.ToLower(item.Title + " " + item.Description)
+// articleText is the text keywords are matched against
+func articleText(item *gofeed.Item, extracted *ExtractedContent) string {
+	text := item.Title + " " + item.Description
+	if extracted != nil {
+		text += " " + extracted.Body
+	}
+	return text
+}
+
+func processArticle(item *gofeed.Item, extracted *ExtractedContent, keywords map[string]string) *Article {
+	articleText := articleText(item, extracted)
+	lowerText := strings.ToLower(articleText)
+	if _, excluded := excludedBy(articleText, lowerText, settings.Exclude); excluded {
+		return nil
+	}
+	author, authorRule, listed := authorRuleFor(itemAuthors(item, extracted))
+	if listed && authorRule.Never {
+		return nil
+	}
 	var matchedKeywords []string
+	var score float64
 
+	// Terms are visited in order so tags come out the same on every run
+	terms := make([]string, 0, len(keywords))
 	for keyword := range keywords {
-		if strings.Contains(articleText, strings.ToLower(keyword)) {
-			matchedKeywords = append(matchedKeywords, keyword)
+		terms = append(terms, keyword)
+	}
+	sort.Strings(terms)
+
+	seenTags := make(map[string]struct{})
+	tagWeights := make(map[string]float64)
+	for _, keyword := range terms {
+		if !matchesKeyword(articleText, lowerText, keyword) {
+			continue
+		}
+		// Synonyms in the same category collapse into one tag, which
+		// scores the highest weight of its matched terms. A synonym's own
+		// exclusions drop only that synonym.
+		tag := tagFor(keyword)
+		if keyword != tag {
+			if _, excluded := excludedBy(articleText, lowerText, settings.KeywordOptions[keyword].Exclude); excluded {
+				continue
+			}
+		}
+		if _, exists := seenTags[tag]; exists {
+			if weight, matched := tagWeights[tag]; matched {
+				tagWeights[tag] = max(weight, keywordWeight(keyword, tag))
+			}
+			continue
+		}
+		seenTags[tag] = struct{}{}
+		if _, excluded := excludedBy(articleText, lowerText, excludedFor(tag)); excluded {
+			continue
 		}
+		matchedKeywords = append(matchedKeywords, tag)
+		tagWeights[tag] = keywordWeight(keyword, tag)
+	}
+	for _, tag := range matchedKeywords {
+		score += tagWeights[tag]
 	}
 
+	// Followed authors are notified whatever the keywords and score say
+	if listed && authorRule.Always {
+		if len(matchedKeywords) == 0 {
+			matchedKeywords = []string{followedAuthorTag}
+		}
+		article := newArticle(item, extracted, articleText, matchedKeywords, score)
+		if article != nil {
+			article.FollowedAuthor = author
+		}
+		return article
+	}
 	if len(matchedKeywords) == 0 {
 		return nil
 	}
+	if scoringEnabled() {
+		score += boosterScore(articleText, lowerText, keywords, seenTags)
+		if score < settings.Scoring.Threshold {
+			return nil
+		}
+	}
+
+	return newArticle(item, extracted, articleText, matchedKeywords, score)
+}
+
+// newArticle builds a matched article with the details extracted from its
+// text; it returns nil when the bounty filter rejects it
+func newArticle(item *gofeed.Item, extracted *ExtractedContent, articleText string, tags []string, score float64) *Article {
+	bounty := extractBounty(articleText)
+	if !bountyAllowed(bounty) {
+		return nil
+	}
+	severity := articleSeverity(articleText, tags)
+	if !severityAllowed(severity) {
+		return nil
+	}
 
-	return &Article{
+	article := &Article{
 		Title:       item.Title,
 		Description: item.Description,
 		Link:        item.Link,
 		Published:   item.Published,
-		Keywords:    matchedKeywords,
+		Keywords:    tags,
+		Video:       isVideo(item),
+		Exploit:     exploitInfo(item),
+		PaidOnly:    isPaidOnly(item),
+		Score:       score,
+		CVEs:        extractCVEs(articleText),
+		Bounty:      bounty,
+		Programs:    extractPrograms(articleText),
+		Severity:    severity,
+		Language:    detectLanguage(articleText),
+		ImageURL:    itemImage(item),
+	}
+	article.Author = strings.Join(itemAuthors(item, extracted), ", ")
+	if extracted != nil {
+		article.Summary = extracted.Excerpt
+		article.HasPoC = extracted.HasPoC
+		if extracted.Image != "" {
+			article.ImageURL = extracted.Image
+		}
+		article.WordCount = len(strings.Fields(extracted.Body))
+		if settings.Readability {
+			article.Content = extracted.Body
+		}
 	}
+	return article
 }
 
-func formatTelegramMessage(article *Article, keyword string) string {
-	cleanedLink := cleanURL(article.Link)
+// articleNotification formats an article, with its preview image or a large
+// link preview when telegram.previews is on
+func articleNotification(article *Article, keyword string) Notification {
+	notification := Notification{
+		Text:      formatTelegramMessage(article, keyword),
+		ParseMode: settings.Telegram.ParseMode,
+		Article:   article,
+		Tag:       keyword,
+	}
+	if settings.Telegram.Previews {
+		notification.ImageURL = article.ImageURL
+		notification.PreviewURL = article.Link
+	}
+	if settings.Telegram.Buttons {
+		notification.Buttons = articleKeyboard(article)
+	}
+	return notification
+}
 
+// readerLink cleans an article link and sends Medium through freedium
+func readerLink(link string) string {
+	cleanedLink := cleanURL(link)
 	if strings.Contains(cleanedLink, "medium.com") {
 		cleanedLink = fmt.Sprintf("https://freedium.cfd/%s", cleanedLink)
 	}
+	return cleanedLink
+}
+
+func formatTelegramMessage(article *Article, keyword string) string {
+	cleanedLink := readerLink(article.Link)
 
-	return fmt.Sprintf("▶ %s\nPublished: %s\nLink: %s\nTags: %s",
-		article.Title, article.Published, cleanedLink, keyword)
+	marker := "▶"
+	if article.Video {
+		marker = "🎬 Video:"
+	}
+	// Source-specific lines between the date and the link
+	var details string
+	if article.Exploit != nil {
+		marker = "💥 Exploit:"
+		details = fmt.Sprintf("Type: %s\n", article.Exploit)
+	}
+	if article.PaidOnly {
+		details += "🔒 Paid subscribers only\n"
+	}
+	if article.HasPoC {
+		details += "🧪 PoC included\n"
+	}
+	if article.WordCount > 0 {
+		details += fmt.Sprintf("📖 %s (%d words)\n", readingTime(article.WordCount), article.WordCount)
+	}
+	if len(article.CVEs) > 0 {
+		details += fmt.Sprintf("CVE: %s\n", strings.Join(article.CVEs, ", "))
+	}
+	for _, detail := range article.CVEDetails {
+		details += fmt.Sprintf("🛡 %s\n", detail)
+	}
+	if article.Author != "" {
+		details += fmt.Sprintf("Author: %s\n", article.Author)
+	}
+	if article.FollowedAuthor != "" {
+		details += fmt.Sprintf("⭐ Followed author: %s\n", article.FollowedAuthor)
+	}
+	if article.Severity != "" {
+		details += fmt.Sprintf("Severity: %s\n", article.Severity)
+	}
+	if len(article.Programs) > 0 {
+		details += fmt.Sprintf("Program: %s\n", strings.Join(article.Programs, ", "))
+	}
+	if article.Bounty != nil {
+		details += fmt.Sprintf("💰 Bounty: %s\n", article.Bounty.Text)
+	}
+	if article.Relevance > 0 {
+		details += fmt.Sprintf("🤖 No keyword match, relevance %.2f\n", article.Relevance)
+	}
+	if scoringEnabled() {
+		details += fmt.Sprintf("Score: %g\n", article.Score)
+	}
+	f := messageFormatter{mode: settings.Telegram.ParseMode}
+	link := "Link: " + f.link("Read the article", cleanedLink)
+	if article.ArchiveURL != "" {
+		link += "\n" + f.escape("Archive: ") + f.link("Wayback Machine", article.ArchiveURL)
+	}
+	return fmt.Sprintf("%s %s\n%s\n%s%s\nTags: %s",
+		f.escape(marker), f.bold(article.Title), f.escape("Published: "+article.Published),
+		f.escape(details), link, f.hashtag(keyword))
 }
 
 // cleanURL removes tracking parameters (e.g., ?source=...) from URLs
@@ -671,29 +1713,168 @@ func cleanURL(rawURL string) string {
 	return parsed.String()
 }
 
-func sendToTelegram(message, botToken, channelID, messageThreadID string) {
-	url := fmt.Sprintf(telegramAPITemplate, botToken)
+// SentMessage identifies a delivered Telegram message, so it can later be
+// edited, deleted or replied to
+type SentMessage struct {
+	ChatID    string `json:"chat_id"`
+	ThreadID  string `json:"thread_id,omitempty"`
+	MessageID int    `json:"message_id"`
+}
+
+// sendToTelegram delivers a notification and returns the sent message, or nil
+// when it was printed, held for quiet hours or could not be delivered
+func sendToTelegram(ctx context.Context, notification Notification, botToken, channelID, messageThreadID string) *SentMessage {
+	if notification.Article != nil {
+		if text, ok := renderTemplate(notification.Article, notification.Tag, channelID, notification.ParseMode); ok {
+			notification.Text = text
+		}
+	}
+	text := messageFormatter{mode: notification.ParseMode}.escape(environmentPrefix()) + notification.Text
+	if dryRun {
+		fmt.Println(color.YellowString("[dry-run] would send to chat %s thread %s:", channelID, messageThreadID))
+		if notification.ImageURL != "" {
+			fmt.Println(color.YellowString("[dry-run] with image %s", notification.ImageURL))
+		}
+		fmt.Println(text)
+		return nil
+	}
 
 	telegramMessage := TelegramMessage{
-		ChatID:          channelID + "_" + messageThreadID,
-		Text:            message,
+		ChatID:          channelID,
 		MessageThreadID: messageThreadID,
+		ParseMode:       notification.ParseMode,
+		ReplyMarkup:     notification.Buttons,
+	}
+
+	quietEnd, inQuietHours := quietUntil(time.Now())
+	if inQuietHours && !settings.QuietHours.Hold {
+		telegramMessage.DisableNotification = true
+	}
+	if inQuietHours && settings.QuietHours.Hold {
+		telegramMessage.Text = text
+		if notification.PreviewURL != "" {
+			telegramMessage.LinkPreviewOptions = &LinkPreviewOptions{URL: notification.PreviewURL, PreferLargeMedia: true}
+		}
+		holdTelegram("sendMessage", telegramMessage, queuedArticle(notification), quietEnd)
+		return nil
+	}
+	sent := func(messageID int) *SentMessage {
+		recordTelegramDelivery()
+		return &SentMessage{ChatID: channelID, ThreadID: messageThreadID, MessageID: messageID}
 	}
 
+	// Photos carry the text as a caption, which Telegram caps
+	if notification.ImageURL != "" && utf8.RuneCountInString(text) <= telegramCaptionLimit {
+		photo := telegramMessage
+		photo.Photo = notification.ImageURL
+		photo.Caption = text
+		messageID, err := deliverTelegram(ctx, fmt.Sprintf(telegramMethodTemplate, botToken, "sendPhoto"), photo)
+		if err == nil {
+			return sent(messageID)
+		}
+		// Telegram could not fetch the image; fall back to text
+		printError(fmt.Sprintf("sending photo to Telegram: %v", err))
+	}
+
+	telegramMessage.Text = text
+	if notification.PreviewURL != "" {
+		telegramMessage.LinkPreviewOptions = &LinkPreviewOptions{URL: notification.PreviewURL, PreferLargeMedia: true}
+	}
+	messageID, err := deliverTelegram(ctx, fmt.Sprintf(telegramMethodTemplate, botToken, "sendMessage"), telegramMessage)
+	if err != nil {
+		printError(fmt.Sprintf("sending message to Telegram: %v", err))
+		recordTelegramFailure(text, telegramMessage.ParseMode)
+		if !permanentTelegramError(err) {
+			enqueueTelegram("sendMessage", telegramMessage, queuedArticle(notification), err)
+		}
+		return nil
+	}
+	return sent(messageID)
+}
+
+// deliverTelegram posts a message, waiting out rate limits up to maxRetries
+// times, and returns the ID Telegram gave it
+func deliverTelegram(ctx context.Context, url string, telegramMessage TelegramMessage) (int, error) {
 	jsonData, err := json.Marshal(telegramMessage)
 	if err != nil {
-		printError(fmt.Sprintf("marshalling Telegram message: %v", err))
-		return
+		return 0, fmt.Errorf("marshalling Telegram message: %w", err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		messageID, retryAfter, err := postTelegramMessage(ctx, url, jsonData)
+		if err == nil {
+			return messageID, nil
+		}
+		if attempt == maxRetries {
+			return 0, fmt.Errorf("giving up after %d attempts: %w", attempt+1, err)
+		}
+
+		switch {
+		case retryAfter > 0:
+			printStatus(fmt.Sprintf("Telegram asked to retry after %s", retryAfter), color.FgYellow)
+		case permanentTelegramError(err):
+			// Bad requests such as an unknown chat do not improve with retries
+			return 0, err
+		default:
+			// Network errors and server errors are retried with backoff
+			retryAfter = retryBaseDelay << attempt
+			printStatus(fmt.Sprintf("Telegram send failed (%v), retrying in %s", err, retryAfter), color.FgYellow)
+		}
+
+		select {
+		case <-time.After(retryAfter):
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		}
 	}
+}
 
-	resp, err := http.Post(url, "application/json", bytes.NewBuffer(jsonData))
+// telegramError is the body of a failed Bot API call
+type telegramError struct {
+	Description string `json:"description"`
+	Parameters  struct {
+		RetryAfter int `json:"retry_after"`
+	} `json:"parameters"`
+}
+
+// postTelegramMessage sends one request. When Telegram rate limits it, the
+// returned duration says how long to wait, from the body's retry_after or the
+// Retry-After header.
+func postTelegramMessage(ctx context.Context, url string, jsonData []byte) (int, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonData))
 	if err != nil {
-		printError(fmt.Sprintf("sending message to Telegram: %v", err))
-		return
+		return 0, 0, fmt.Errorf("creating Telegram request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, 0, stripBotURL(err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		printError(fmt.Sprintf("Telegram API responded with status: %d", resp.StatusCode))
+	if resp.StatusCode == http.StatusOK {
+		// The message is out even if its ID cannot be read
+		var result struct {
+			Result struct {
+				MessageID int `json:"message_id"`
+			} `json:"result"`
+		}
+		json.NewDecoder(resp.Body).Decode(&result)
+		return result.Result.MessageID, 0, nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	httpErr := &HTTPError{StatusCode: resp.StatusCode, Body: bytes.TrimSpace(body)}
+	var apiErr telegramError
+	if json.Unmarshal(body, &apiErr) == nil && apiErr.Description != "" {
+		httpErr.Body = []byte(apiErr.Description)
+	}
+	if isRateLimited(resp.StatusCode) {
+		httpErr.RetryAfter = time.Duration(apiErr.Parameters.RetryAfter) * time.Second
+		if httpErr.RetryAfter == 0 {
+			httpErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		}
 	}
+	return 0, httpErr.RetryAfter, httpErr
 }
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{"Mon, 02 Jan 2006 15:04:05 GMT", time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{"Mon, 02 Jan 2006 15:04:05 +0000", time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{"2006-01-02 15:04:05", time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)},
+		{"2024", time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"2024-05", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{"2024-05-06", time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)},
+		{"2024-05-06T10:20Z", time.Date(2024, 5, 6, 10, 20, 0, 0, time.UTC)},
+		{"2024-05-06T12:20+02:00", time.Date(2024, 5, 6, 10, 20, 0, 0, time.UTC)},
+		{"2024-05-06T10:20:30Z", time.Date(2024, 5, 6, 10, 20, 30, 0, time.UTC)},
+		{"2024-05-06T11:20:30.45+01:00", time.Date(2024, 5, 6, 10, 20, 30, 450000000, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := parseDate(tt.input)
+		if err != nil {
+			t.Errorf("parseDate(%q): %v", tt.input, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseDate(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestParseDateInvalid(t *testing.T) {
+	for _, input := range []string{"", "yesterday", "2024-13-01", "06/05/2024"} {
+		if _, err := parseDate(input); err == nil {
+			t.Errorf("parseDate(%q) succeeded, want an error", input)
+		}
+	}
+}
+
+// useSettings installs settings and their resolved categories for one test
+func useSettings(t *testing.T, s Settings) {
+	t.Helper()
+	saved := settings
+	savedTerms, savedThreads, savedDisabled := categoryOfTerm, categoryThreads, disabledTerms
+	t.Cleanup(func() {
+		settings = saved
+		categoryOfTerm, categoryThreads, disabledTerms = savedTerms, savedThreads, savedDisabled
+	})
+
+	terms, threads, disabled, err := resolveCategories(s.Categories)
+	if err != nil {
+		t.Fatal(err)
+	}
+	settings = s
+	categoryOfTerm, categoryThreads, disabledTerms = terms, threads, disabled
+}
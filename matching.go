@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// A keyword written as /pattern/ is a regular expression matched
+// case-insensitively against the article text, e.g. /CVE-\d{4}-\d+/ or /\bRCE\b/.
+// It routes to its thread like any other keyword. Plain keywords match as
+// case-insensitive substrings unless MatchOptions say otherwise; stemming and
+// fuzzy matching are only tried when the exact match fails.
+
+// MatchOptions tune how a plain keyword matches. Unset fields fall back from
+// the keyword to its category to the global matching section.
+type MatchOptions struct {
+	// WholeWord stops "idor" from matching inside "corridor"
+	WholeWord *bool `yaml:"whole_word"`
+	// CaseSensitive makes "LDAP" ignore "ldap" in running text
+	CaseSensitive *bool `yaml:"case_sensitive"`
+	// Stem lets "authentication bypass" match "authenticated bypasses"
+	Stem *bool `yaml:"stem"`
+	// Fuzzy accepts words within this edit distance (1 or 2), catching
+	// misspellings such as "deserialisation" or "authenticaton"
+	Fuzzy *int `yaml:"fuzzy"`
+	// Exclude drops the keyword's match when the article also contains one
+	// of these terms. Only read from keyword_options; categories and the
+	// global list have exclude lists of their own.
+	Exclude []string `yaml:"exclude"`
+}
+
+// matchLayers lists the options that apply to a term, most specific first
+func matchLayers(term string) []MatchOptions {
+	layers := []MatchOptions{settings.KeywordOptions[term]}
+	if category, exists := categoryOfTerm[term]; exists {
+		layers = append(layers, settings.Categories[category].Match)
+	}
+	return append(layers, settings.Matching)
+}
+
+// matchOptionsFor resolves the options in effect for a term
+func matchOptionsFor(term string) (wholeWord, caseSensitive bool) {
+	layers := matchLayers(term)
+	for _, layer := range layers {
+		if layer.WholeWord != nil {
+			wholeWord = *layer.WholeWord
+			break
+		}
+	}
+	for _, layer := range layers {
+		if layer.CaseSensitive != nil {
+			caseSensitive = *layer.CaseSensitive
+			break
+		}
+	}
+	return wholeWord, caseSensitive
+}
+
+// keywordPatterns caches compiled keyword expressions; a nil entry marks a
+// pattern that failed to compile and was already reported
+var keywordPatterns = struct {
+	mu       sync.Mutex
+	compiled map[string]*regexp.Regexp
+}{compiled: make(map[string]*regexp.Regexp)}
+
+// regexKeyword returns the pattern of a /pattern/ keyword
+func regexKeyword(term string) (string, bool) {
+	if len(term) > 2 && strings.HasPrefix(term, "/") && strings.HasSuffix(term, "/") {
+		return term[1 : len(term)-1], true
+	}
+	return "", false
+}
+
+// compileKeyword compiles a regex keyword's pattern case-insensitively
+func compileKeyword(pattern string) (*regexp.Regexp, error) {
+	re, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid keyword pattern /%s/: %w", pattern, err)
+	}
+	return re, nil
+}
+
+// cachedPattern compiles an expression once; invalid ones are reported once
+// and never match
+func cachedPattern(expr string, compile func(string) (*regexp.Regexp, error)) *regexp.Regexp {
+	keywordPatterns.mu.Lock()
+	defer keywordPatterns.mu.Unlock()
+
+	if re, exists := keywordPatterns.compiled[expr]; exists {
+		return re
+	}
+	re, err := compile(expr)
+	if err != nil {
+		printError(err.Error())
+	}
+	keywordPatterns.compiled[expr] = re
+	return re
+}
+
+// plainKeywordPattern builds the expression for a plain keyword with options;
+// word boundaries are anything but letters and digits, so "2FA Bypass" and
+// "C++" work where \b would not
+func plainKeywordPattern(term string, wholeWord, caseSensitive bool) string {
+	pattern := regexp.QuoteMeta(term)
+	if wholeWord {
+		pattern = `(?:^|[^\p{L}\p{N}_])` + pattern + `(?:$|[^\p{L}\p{N}_])`
+	}
+	if !caseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	return pattern
+}
+
+// matchesKeyword reports whether an article's text contains a keyword.
+// lowerText is text lowercased, used for plain substring keywords.
+func matchesKeyword(text, lowerText, term string) bool {
+	if pattern, isRegex := regexKeyword(term); isRegex {
+		re := cachedPattern(pattern, compileKeyword)
+		return re != nil && re.MatchString(text)
+	}
+
+	wholeWord, caseSensitive := matchOptionsFor(term)
+	if !wholeWord && !caseSensitive {
+		if strings.Contains(lowerText, strings.ToLower(term)) {
+			return true
+		}
+	} else if re := cachedPattern(plainKeywordPattern(term, wholeWord, caseSensitive), regexp.Compile); re != nil && re.MatchString(text) {
+		return true
+	}
+
+	if stem, distance := approximateOptionsFor(term); stem || distance > 0 {
+		return approximateMatch(lowerText, term, stem, distance)
+	}
+	return false
+}
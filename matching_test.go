@@ -0,0 +1,109 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMatchesKeywordRegex(t *testing.T) {
+	useSettings(t, Settings{})
+
+	tests := []struct {
+		text, term string
+		want       bool
+	}{
+		{"Exploiting CVE-2024-12345 in the wild", `/CVE-\d{4}-\d+/`, true},
+		{"exploiting cve-2024-12345", `/CVE-\d{4}-\d+/`, true},
+		{"No identifiers here", `/CVE-\d{4}-\d+/`, false},
+		{"Pre-auth RCE in a router", `/\bRCE\b/`, true},
+		{"Source code review", `/\bRCE\b/`, false},
+		// An invalid pattern never matches
+		{"anything", `/([/`, false},
+		// Too short to be a pattern, matched as text
+		{"a // comment", "//", true},
+	}
+	for _, tt := range tests {
+		if got := matchesKeyword(tt.text, strings.ToLower(tt.text), tt.term); got != tt.want {
+			t.Errorf("matchesKeyword(%q, %q) = %v, want %v", tt.text, tt.term, got, tt.want)
+		}
+	}
+}
+
+func TestMatchesKeywordOptions(t *testing.T) {
+	on, off := true, false
+
+	tests := []struct {
+		name     string
+		settings Settings
+		text     string
+		term     string
+		want     bool
+	}{
+		{
+			name: "substring by default",
+			text: "Walking down the corridor",
+			term: "idor",
+			want: true,
+		},
+		{
+			name:     "global whole word",
+			settings: Settings{Matching: MatchOptions{WholeWord: &on}},
+			text:     "Walking down the corridor",
+			term:     "idor",
+			want:     false,
+		},
+		{
+			name:     "whole word at punctuation",
+			settings: Settings{Matching: MatchOptions{WholeWord: &on}},
+			text:     "An IDOR, then account takeover",
+			term:     "idor",
+			want:     true,
+		},
+		{
+			name:     "whole word with symbols",
+			settings: Settings{Matching: MatchOptions{WholeWord: &on}},
+			text:     "Fuzzing C++ parsers",
+			term:     "c++",
+			want:     true,
+		},
+		{
+			name:     "keyword overrides global",
+			settings: Settings{Matching: MatchOptions{WholeWord: &on}, KeywordOptions: map[string]MatchOptions{"idor": {WholeWord: &off}}},
+			text:     "Walking down the corridor",
+			term:     "idor",
+			want:     true,
+		},
+		{
+			name: "category overrides global",
+			settings: Settings{
+				Matching:   MatchOptions{WholeWord: &off},
+				Categories: map[string]Category{"access": {Terms: []string{"idor"}, Match: MatchOptions{WholeWord: &on}}},
+			},
+			text: "Walking down the corridor",
+			term: "idor",
+			want: false,
+		},
+		{
+			name:     "case sensitive",
+			settings: Settings{KeywordOptions: map[string]MatchOptions{"LDAP": {CaseSensitive: &on}}},
+			text:     "an ldap-like syntax",
+			term:     "LDAP",
+			want:     false,
+		},
+		{
+			name:     "case sensitive match",
+			settings: Settings{KeywordOptions: map[string]MatchOptions{"LDAP": {CaseSensitive: &on}}},
+			text:     "LDAP injection",
+			term:     "LDAP",
+			want:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useSettings(t, tt.settings)
+			if got := matchesKeyword(tt.text, strings.ToLower(tt.text), tt.term); got != tt.want {
+				t.Errorf("matchesKeyword(%q, %q) = %v, want %v", tt.text, tt.term, got, tt.want)
+			}
+		})
+	}
+}
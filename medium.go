@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// mediumPrefix marks data.txt shorthand for Medium feeds:
+//
+//	medium:tag:bug-bounty,xss     one feed per tag
+//	medium:@someone               a user's posts
+//	medium:infosec-writeups       a publication
+const (
+	mediumPrefix    = "medium:"
+	mediumTagPrefix = "tag:"
+	mediumFeedBase  = "https://medium.com/feed/"
+)
+
+// expandMediumEntry turns a medium: shorthand line into feed URLs
+func expandMediumEntry(entry string) ([]string, error) {
+	spec := strings.TrimSpace(strings.TrimPrefix(entry, mediumPrefix))
+
+	if tags, found := strings.CutPrefix(spec, mediumTagPrefix); found {
+		var feeds []string
+		for _, tag := range strings.Split(tags, ",") {
+			if slug := mediumSlug(tag); slug != "" {
+				feeds = append(feeds, mediumFeedBase+"tag/"+url.PathEscape(slug))
+			}
+		}
+		if len(feeds) == 0 {
+			return nil, fmt.Errorf("medium entry %q names no tags", entry)
+		}
+		return feeds, nil
+	}
+
+	if spec == "" || strings.ContainsAny(spec, "/ ") {
+		return nil, fmt.Errorf("malformed medium entry: %q", entry)
+	}
+	return []string{mediumFeedBase + spec}, nil
+}
+
+// mediumSlug matches how Medium writes tags in URLs: "Bug Bounty" is bug-bounty
+func mediumSlug(tag string) string {
+	fields := strings.FieldsFunc(strings.ToLower(tag), func(r rune) bool {
+		return r == ' ' || r == '_' || r == '-'
+	})
+	return strings.Join(fields, "-")
+}
+
+// isMediumFeed recognizes Medium feeds, including publications on custom domains
+func isMediumFeed(feedURL string, feed *gofeed.Feed) bool {
+	domain := getDomain(feedURL)
+	return domain == "medium.com" || strings.HasSuffix(domain, ".medium.com") || feed.Generator == "Medium"
+}
+
+// normalizeMediumLinks drops the ?source=rss-... tracking parameter Medium
+// adds per feed, so a post listed under several tags dedups to one link
+func normalizeMediumLinks(items []*gofeed.Item) {
+	for _, item := range items {
+		item.Link = stripMediumSource(item.Link)
+	}
+}
+
+// stripMediumSource removes Medium's per-feed source parameter from a link
+func stripMediumSource(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	query := u.Query()
+	if !strings.HasPrefix(query.Get("source"), "rss") {
+		return link
+	}
+	query.Del("source")
+	u.RawQuery = query.Encode()
+	return u.String()
+}
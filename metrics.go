@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// runMetrics counts what the hunter did since the process started, exported
+// in the Prometheus text format on /metrics in daemon mode
+var runMetrics = struct {
+	mu               sync.Mutex
+	feedsProcessed   int
+	fetchErrors      map[string]int
+	articlesMatched  map[string]int
+	telegramFailures int
+	runs             int
+	lastRunDuration  time.Duration
+	lastRunFinished  time.Time
+	lastRunSucceeded time.Time
+	deliveryLatency  map[string]*histogram
+}{
+	fetchErrors:     make(map[string]int),
+	articlesMatched: make(map[string]int),
+	deliveryLatency: make(map[string]*histogram),
+}
+
+// deliveryLatencyBuckets are the upper bounds, in seconds, of the
+// publish-to-delivery latency histogram: five minutes to a week
+var deliveryLatencyBuckets = []float64{300, 900, 1800, 3600, 3 * 3600, 6 * 3600, 12 * 3600, 86400, 2 * 86400, 7 * 86400}
+
+// histogram counts observations per bucket; counts[i] holds those at most
+// deliveryLatencyBuckets[i] and above the previous bound
+type histogram struct {
+	counts []int
+	sum    float64
+	count  int
+}
+
+func (h *histogram) observe(value float64) {
+	h.sum += value
+	h.count++
+	for i, bound := range deliveryLatencyBuckets {
+		if value <= bound {
+			h.counts[i]++
+			return
+		}
+	}
+}
+
+func metricFeedProcessed() {
+	runMetrics.mu.Lock()
+	defer runMetrics.mu.Unlock()
+	runMetrics.feedsProcessed++
+}
+
+func metricFeedsProcessed() int {
+	runMetrics.mu.Lock()
+	defer runMetrics.mu.Unlock()
+	return runMetrics.feedsProcessed
+}
+
+func metricFetchError(domain string) {
+	runMetrics.mu.Lock()
+	defer runMetrics.mu.Unlock()
+	runMetrics.fetchErrors[domain]++
+}
+
+func metricArticleMatched(keyword string) {
+	runMetrics.mu.Lock()
+	defer runMetrics.mu.Unlock()
+	runMetrics.articlesMatched[keyword]++
+}
+
+func metricTelegramFailure() {
+	runMetrics.mu.Lock()
+	defer runMetrics.mu.Unlock()
+	runMetrics.telegramFailures++
+}
+
+// metricDeliveryLatency records how long after publication an article from
+// the feed reached Telegram, labelled by the feed's domain
+func metricDeliveryLatency(feedURL string, seconds float64) {
+	runMetrics.mu.Lock()
+	defer runMetrics.mu.Unlock()
+	source := getDomain(feedURL)
+	h, exists := runMetrics.deliveryLatency[source]
+	if !exists {
+		h = &histogram{counts: make([]int, len(deliveryLatencyBuckets))}
+		runMetrics.deliveryLatency[source] = h
+	}
+	h.observe(seconds)
+}
+
+func metricRunFinished(duration time.Duration) {
+	runMetrics.mu.Lock()
+	defer runMetrics.mu.Unlock()
+	runMetrics.runs++
+	runMetrics.lastRunDuration = duration
+	runMetrics.lastRunFinished = time.Now()
+}
+
+// handleMetrics writes the counters in the Prometheus text exposition format
+func handleMetrics(w http.ResponseWriter, r *http.Request) {
+	runMetrics.mu.Lock()
+	defer runMetrics.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	// HUNTER_ENV as a label, to join on or tell instances apart
+	fmt.Fprintf(w, "# HELP hunter_info Instance information.\n# TYPE hunter_info gauge\nhunter_info{env=\"%s\"} 1\n",
+		labelEscaper.Replace(firstNonEmpty(environment, "prod")))
+	writeMetric(w, "hunter_feeds_processed_total", "counter", "Feeds fetched successfully.", float64(runMetrics.feedsProcessed))
+	writeLabeledMetric(w, "hunter_fetch_errors_total", "counter", "Failed feed fetches by domain.", "domain", runMetrics.fetchErrors)
+	writeLabeledMetric(w, "hunter_articles_matched_total", "counter", "Notified articles by keyword or category.", "keyword", runMetrics.articlesMatched)
+	writeHistogram(w, "hunter_delivery_latency_seconds", "Time from article publication to Telegram delivery, by source domain.", "source", runMetrics.deliveryLatency)
+	writeMetric(w, "hunter_telegram_send_failures_total", "counter", "Telegram messages that could not be delivered.", float64(runMetrics.telegramFailures))
+	writeMetric(w, "hunter_runs_total", "counter", "Completed runs.", float64(runMetrics.runs))
+	writeMetric(w, "hunter_last_run_duration_seconds", "gauge", "Duration of the last run.", runMetrics.lastRunDuration.Seconds())
+	if !runMetrics.lastRunFinished.IsZero() {
+		writeMetric(w, "hunter_last_run_timestamp_seconds", "gauge", "Unix time the last run finished.", float64(runMetrics.lastRunFinished.Unix()))
+	}
+}
+
+func writeMetric(w io.Writer, name, kind, help string, value float64) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n%s %g\n", name, help, name, kind, name, value)
+}
+
+func writeLabeledMetric(w io.Writer, name, kind, help, label string, values map[string]int) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(w, "%s{%s=\"%s\"} %d\n", name, label, labelEscaper.Replace(key), values[key])
+	}
+}
+
+func writeHistogram(w io.Writer, name, help, label string, values map[string]*histogram) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", name, help, name)
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		h := values[key]
+		value := labelEscaper.Replace(key)
+		cumulative := 0
+		for i, bound := range deliveryLatencyBuckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(w, "%s_bucket{%s=\"%s\",le=\"%g\"} %d\n", name, label, value, bound, cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket{%s=\"%s\",le=\"+Inf\"} %d\n", name, label, value, h.count)
+		fmt.Fprintf(w, "%s_sum{%s=\"%s\"} %g\n", name, label, value, h.sum)
+		fmt.Fprintf(w, "%s_count{%s=\"%s\"} %d\n", name, label, value, h.count)
+	}
+}
+
+// labelEscaper escapes label values as the exposition format requires
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
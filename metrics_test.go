@@ -0,0 +1,57 @@
+package main
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// scrapeMetrics returns the /metrics output
+func scrapeMetrics(t *testing.T) string {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	handleMetrics(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	return recorder.Body.String()
+}
+
+func TestDeliveryLatencyHistogram(t *testing.T) {
+	metricDeliveryLatency("https://latency.example/feed.xml", 600)
+	metricDeliveryLatency("https://latency.example/other.xml", 2*86400)
+	metricDeliveryLatency("https://latency.example/other.xml", 30*86400)
+
+	output := scrapeMetrics(t)
+	for _, want := range []string{
+		`hunter_delivery_latency_seconds_bucket{source="latency.example",le="300"} 0`,
+		`hunter_delivery_latency_seconds_bucket{source="latency.example",le="900"} 1`,
+		`hunter_delivery_latency_seconds_bucket{source="latency.example",le="172800"} 2`,
+		`hunter_delivery_latency_seconds_bucket{source="latency.example",le="+Inf"} 3`,
+		`hunter_delivery_latency_seconds_count{source="latency.example"} 3`,
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("metrics lack %q:\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "feed.xml") {
+		t.Error("delivery latency is labelled by feed URL, want the domain only")
+	}
+}
+
+func TestMetricsEnvironmentLabel(t *testing.T) {
+	saved := environment
+	t.Cleanup(func() { environment = saved })
+
+	tests := []struct {
+		environment string
+		want        string
+	}{
+		{"", `hunter_info{env="prod"} 1`},
+		{"prod", `hunter_info{env="prod"} 1`},
+		{"staging", `hunter_info{env="staging"} 1`},
+	}
+	for _, tt := range tests {
+		environment = tt.environment
+		if output := scrapeMetrics(t); !strings.Contains(output, tt.want) {
+			t.Errorf("HUNTER_ENV %q: metrics lack %q:\n%s", tt.environment, tt.want, output)
+		}
+	}
+}
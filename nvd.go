@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// nvdPrefix marks data.txt shorthand for recently published CVEs, optionally
+// with a minimum CVSS base score: "nvd:" or "nvd:7.0". CVEs then go through the
+// usual keyword matching, so only technologies in the taxonomy are notified.
+const (
+	nvdPrefix       = "nvd:"
+	nvdAPIURL       = "https://services.nvd.nist.gov/rest/json/cves/2.0"
+	nvdDetailURL    = "https://nvd.nist.gov/vuln/detail/"
+	nvdMinCVSSParam = "minCvss"
+	nvdWindow       = 7 * 24 * time.Hour
+	nvdTimeLayout   = "2006-01-02T15:04:05.000"
+)
+
+// NVDSettings holds the optional API key, which raises NVD's rate limit, and
+// whether CVEs mentioned in articles are looked up for their details
+type NVDSettings struct {
+	APIKey string `yaml:"api_key"`
+	Enrich bool   `yaml:"enrich"`
+}
+
+// expandNVDEntry turns an nvd: line into the source URL; the score threshold
+// rides along as a parameter that fetchNVD strips before calling the API
+func expandNVDEntry(entry string) ([]string, error) {
+	threshold := strings.TrimSpace(strings.TrimPrefix(entry, nvdPrefix))
+	if threshold == "" {
+		return []string{nvdAPIURL}, nil
+	}
+	if score, err := strconv.ParseFloat(threshold, 64); err != nil || score < 0 || score > 10 {
+		return nil, fmt.Errorf("nvd entry %q: threshold must be a CVSS score between 0 and 10", entry)
+	}
+	return []string{nvdAPIURL + "?" + nvdMinCVSSParam + "=" + threshold}, nil
+}
+
+// isNVDSource recognizes NVD CVE API sources
+func isNVDSource(feedURL string) bool {
+	return strings.HasPrefix(feedURL, nvdAPIURL)
+}
+
+type nvdResponse struct {
+	Vulnerabilities []struct {
+		CVE nvdCVE `json:"cve"`
+	} `json:"vulnerabilities"`
+}
+
+type nvdCVE struct {
+	ID           string `json:"id"`
+	Published    string `json:"published"`
+	Descriptions []struct {
+		Lang  string `json:"lang"`
+		Value string `json:"value"`
+	} `json:"descriptions"`
+	Metrics map[string][]struct {
+		CVSSData struct {
+			BaseScore    float64 `json:"baseScore"`
+			BaseSeverity string  `json:"baseSeverity"`
+		} `json:"cvssData"`
+		BaseSeverity string `json:"baseSeverity"`
+	} `json:"metrics"`
+	Weaknesses []struct {
+		Description []struct {
+			Lang  string `json:"lang"`
+			Value string `json:"value"`
+		} `json:"description"`
+	} `json:"weaknesses"`
+	Configurations []struct {
+		Nodes []struct {
+			CPEMatch []struct {
+				Criteria string `json:"criteria"`
+			} `json:"cpeMatch"`
+		} `json:"nodes"`
+	} `json:"configurations"`
+}
+
+// fetchNVD asks the API for CVEs published within the last week and keeps
+// those at or above the source's CVSS threshold
+func fetchNVD(ctx context.Context, feedURL string) ([]*gofeed.Item, error) {
+	source, err := url.Parse(feedURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing NVD source: %w", err)
+	}
+	query := source.Query()
+	minScore := 0.0
+	if threshold := query.Get(nvdMinCVSSParam); threshold != "" {
+		if minScore, err = strconv.ParseFloat(threshold, 64); err != nil {
+			return nil, fmt.Errorf("parsing CVSS threshold: %w", err)
+		}
+	}
+	query.Del(nvdMinCVSSParam)
+
+	now := time.Now().UTC()
+	// Dates without an offset are read as UTC
+	query.Set("pubStartDate", now.Add(-nvdWindow).Format(nvdTimeLayout))
+	query.Set("pubEndDate", now.Format(nvdTimeLayout))
+	source.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	setUserAgent(req)
+	if settings.NVD.APIKey != "" {
+		req.Header.Set("apiKey", settings.NVD.APIKey)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching NVD: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		httpErr := &HTTPError{StatusCode: resp.StatusCode, Body: body}
+		if isRateLimited(resp.StatusCode) {
+			httpErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		}
+		return nil, httpErr
+	}
+
+	var response nvdResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("decoding NVD response: %w", err)
+	}
+
+	var items []*gofeed.Item
+	for _, vulnerability := range response.Vulnerabilities {
+		cve := vulnerability.CVE
+		score, severity := cve.cvss()
+		if score < minScore {
+			continue
+		}
+		items = append(items, cve.item(score, severity))
+	}
+	return items, nil
+}
+
+// cvss returns the highest base score across the CVSS versions NVD lists
+func (c nvdCVE) cvss() (float64, string) {
+	var best float64
+	var severity string
+	for _, metrics := range c.Metrics {
+		for _, metric := range metrics {
+			if metric.CVSSData.BaseScore > best {
+				best = metric.CVSSData.BaseScore
+				severity = firstNonEmpty(metric.CVSSData.BaseSeverity, metric.BaseSeverity)
+			}
+		}
+	}
+	return best, severity
+}
+
+// products lists the distinct "vendor product" pairs of the CVE's CPE matches
+func (c nvdCVE) products() []string {
+	var products []string
+	seen := make(map[string]struct{})
+	for _, configuration := range c.Configurations {
+		for _, node := range configuration.Nodes {
+			for _, match := range node.CPEMatch {
+				// cpe:2.3:a:vendor:product:version:...
+				fields := strings.Split(match.Criteria, ":")
+				if len(fields) < 5 {
+					continue
+				}
+				product := fields[3] + " " + strings.ReplaceAll(fields[4], "_", " ")
+				if _, exists := seen[product]; !exists {
+					seen[product] = struct{}{}
+					products = append(products, product)
+				}
+			}
+		}
+	}
+	return products
+}
+
+// cwes lists the CWE IDs NVD assigned, e.g. CWE-79
+func (c nvdCVE) cwes() []string {
+	var cwes []string
+	seen := make(map[string]struct{})
+	for _, weakness := range c.Weaknesses {
+		for _, d := range weakness.Description {
+			if !strings.HasPrefix(d.Value, "CWE-") {
+				continue
+			}
+			if _, exists := seen[d.Value]; !exists {
+				seen[d.Value] = struct{}{}
+				cwes = append(cwes, d.Value)
+			}
+		}
+	}
+	return cwes
+}
+
+// item turns a CVE into an article; affected CPE products are appended to the
+// description so keywords naming a technology match
+func (c nvdCVE) item(score float64, severity string) *gofeed.Item {
+	var description string
+	for _, d := range c.Descriptions {
+		if d.Lang == "en" {
+			description = d.Value
+			break
+		}
+	}
+
+	if products := c.products(); len(products) > 0 {
+		description += "\nAffected: " + strings.Join(products, ", ")
+	}
+
+	published := c.Published
+	if t, err := time.Parse(nvdTimeLayout, c.Published); err == nil {
+		published = t.Format(time.RFC1123Z)
+	}
+
+	title := c.ID
+	if score > 0 {
+		title = fmt.Sprintf("%s (CVSS %.1f %s)", c.ID, score, severity)
+	}
+	item := &gofeed.Item{
+		Title:       title,
+		Description: description,
+		Link:        nvdDetailURL + c.ID,
+		GUID:        c.ID,
+		Published:   published,
+	}
+	if severity != "" {
+		item.Categories = []string{severity}
+	}
+	return item
+}
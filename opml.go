@@ -0,0 +1,159 @@
+package main
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// OPML is the subset of the OPML 2.0 format used by RSS readers for subscriptions
+type OPML struct {
+	XMLName xml.Name      `xml:"opml"`
+	Version string        `xml:"version,attr"`
+	Title   string        `xml:"head>title"`
+	Body    []OPMLOutline `xml:"body>outline"`
+}
+
+// OPMLOutline is a feed entry or a folder of nested entries
+type OPMLOutline struct {
+	Text     string        `xml:"text,attr"`
+	Title    string        `xml:"title,attr,omitempty"`
+	Type     string        `xml:"type,attr,omitempty"`
+	XMLURL   string        `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string        `xml:"htmlUrl,attr,omitempty"`
+	Outlines []OPMLOutline `xml:"outline"`
+}
+
+// runOPML handles "opml import <file>" and "opml export [file]"
+func runOPML(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: opml import <file> | opml export [file]")
+	}
+
+	switch args[0] {
+	case "import":
+		if len(args) < 2 {
+			return fmt.Errorf("usage: opml import <file>")
+		}
+		added, err := importOPML(args[1], urlsFileName)
+		if err != nil {
+			return err
+		}
+		printStatus(fmt.Sprintf("Imported %d new feeds into %s", added, urlsFileName), color.FgGreen)
+		return nil
+	case "export":
+		out := os.Stdout
+		if len(args) > 1 {
+			file, err := os.Create(args[1])
+			if err != nil {
+				return fmt.Errorf("creating %s: %w", args[1], err)
+			}
+			defer file.Close()
+			out = file
+		}
+		return exportOPML(urlsFileName, out)
+	default:
+		return fmt.Errorf("unknown opml command %q", args[0])
+	}
+}
+
+// importOPML appends feeds from an OPML file that are not yet in the feed list
+func importOPML(opmlFile, feedsFile string) (int, error) {
+	data, err := os.ReadFile(opmlFile)
+	if err != nil {
+		return 0, fmt.Errorf("reading %s: %w", opmlFile, err)
+	}
+
+	var doc OPML
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		return 0, fmt.Errorf("parsing %s: %w", opmlFile, err)
+	}
+
+	existing, err := readURLs(feedsFile)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return 0, err
+	}
+	known := make(map[string]struct{}, len(existing))
+	for _, u := range existing {
+		known[u] = struct{}{}
+	}
+
+	if err := ensureTrailingNewline(feedsFile); err != nil {
+		return 0, err
+	}
+
+	added := 0
+	for _, feedURL := range collectOPMLFeeds(doc.Body) {
+		if _, exists := known[feedURL]; exists {
+			continue
+		}
+		if err := saveURL(feedURL, feedsFile); err != nil {
+			return added, err
+		}
+		known[feedURL] = struct{}{}
+		added++
+	}
+
+	return added, nil
+}
+
+// ensureTrailingNewline keeps appended entries from being glued to a last line
+// that was saved without a newline
+func ensureTrailingNewline(filename string) error {
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) || (err == nil && (len(data) == 0 || data[len(data)-1] == '\n')) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", filename, err)
+	}
+	return saveURL("", filename)
+}
+
+// collectOPMLFeeds flattens nested folders into a list of feed URLs
+func collectOPMLFeeds(outlines []OPMLOutline) []string {
+	var urls []string
+	for _, outline := range outlines {
+		if u := strings.TrimSpace(outline.XMLURL); u != "" {
+			urls = append(urls, u)
+		}
+		urls = append(urls, collectOPMLFeeds(outline.Outlines)...)
+	}
+	return urls
+}
+
+// exportOPML writes the local feed list as OPML; remote list entries are skipped
+// because OPML has no equivalent for them
+func exportOPML(feedsFile string, out io.Writer) error {
+	entries, err := readURLs(feedsFile)
+	if err != nil {
+		return err
+	}
+
+	doc := OPML{Version: "2.0", Title: "Writeup Hunter feeds"}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry, remoteListPrefix) {
+			continue
+		}
+		doc.Body = append(doc.Body, OPMLOutline{
+			Text:   getDomain(entry),
+			Type:   "rss",
+			XMLURL: entry,
+		})
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding OPML: %w", err)
+	}
+
+	if _, err := io.WriteString(out, xml.Header+string(data)+"\n"); err != nil {
+		return fmt.Errorf("writing OPML: %w", err)
+	}
+	return nil
+}
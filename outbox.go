@@ -0,0 +1,214 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+const (
+	outboxFileName = "outbox.json"
+	// Messages still failing after this many runs are dropped
+	maxOutboxAttempts = 20
+)
+
+// QueuedMessage is a notification Telegram did not accept, kept for a later
+// run. The bot token is not stored; the method is resolved again on resend.
+type QueuedMessage struct {
+	Method    string          `json:"method"`
+	Message   TelegramMessage `json:"message"`
+	QueuedAt  time.Time       `json:"queued_at"`
+	Attempts  int             `json:"attempts"`
+	LastError string          `json:"last_error,omitempty"`
+	// HoldUntil keeps a message sent during quiet hours until they end
+	HoldUntil time.Time `json:"hold_until,omitempty"`
+	// Article is the stored article the message announces, marked
+	// delivered once the message goes out
+	Article *QueuedArticle `json:"article,omitempty"`
+}
+
+// QueuedArticle points at an article store record
+type QueuedArticle struct {
+	Store string `json:"store"`
+	Link  string `json:"link"`
+}
+
+// queuedArticle returns the store record a notification announces, if any
+func queuedArticle(notification Notification) *QueuedArticle {
+	if notification.Article == nil || notification.Store == "" {
+		return nil
+	}
+	return &QueuedArticle{Store: notification.Store, Link: notification.Article.Link}
+}
+
+// messageOutbox holds undelivered messages between load and save. In serve
+// mode the health handlers read it while a run fills and flushes it, so every
+// access goes through its methods.
+type messageOutbox struct {
+	mu       sync.Mutex
+	messages []QueuedMessage
+}
+
+var outbox messageOutbox
+
+// load replaces the queued messages with those saved in filename
+func (o *messageOutbox) load(filename string) error {
+	messages, err := readOutbox(filename)
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.messages = messages
+	return err
+}
+
+// save writes the queued messages to filename
+func (o *messageOutbox) save(filename string) error {
+	o.mu.Lock()
+	messages := append([]QueuedMessage(nil), o.messages...)
+	o.mu.Unlock()
+	return writeOutbox(filename, messages)
+}
+
+// pending returns how many messages are queued
+func (o *messageOutbox) pending() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.messages)
+}
+
+func (o *messageOutbox) add(message QueuedMessage) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.messages = append(o.messages, message)
+}
+
+// drain takes every queued message out of the outbox
+func (o *messageOutbox) drain() []QueuedMessage {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	messages := o.messages
+	o.messages = nil
+	return messages
+}
+
+// requeue puts messages back ahead of those queued since the drain
+func (o *messageOutbox) requeue(messages []QueuedMessage) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.messages = append(messages, o.messages...)
+}
+
+func readOutbox(filename string) ([]QueuedMessage, error) {
+	var messages []QueuedMessage
+
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return messages, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", filename, err)
+	}
+	if err := json.Unmarshal(data, &messages); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+	return messages, nil
+}
+
+func writeOutbox(filename string, messages []QueuedMessage) error {
+	if messages == nil {
+		messages = []QueuedMessage{}
+	}
+	data, err := json.MarshalIndent(messages, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding outbox: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", filename, err)
+	}
+	return nil
+}
+
+// permanentTelegramError reports failures that resending will not fix, such
+// as a malformed message or an unknown chat
+func permanentTelegramError(err error) bool {
+	var httpErr *HTTPError
+	return errors.As(err, &httpErr) &&
+		httpErr.StatusCode < http.StatusInternalServerError &&
+		httpErr.StatusCode != http.StatusTooManyRequests
+}
+
+// stripBotURL drops the request URL from a Bot API transport error. The URL
+// carries the bot token, which must not reach logs or the outbox.
+func stripBotURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return fmt.Errorf("%s api.telegram.org: %w", urlErr.Op, urlErr.Err)
+	}
+	return err
+}
+
+// enqueueTelegram keeps a message that failed for a transient reason
+func enqueueTelegram(method string, message TelegramMessage, article *QueuedArticle, err error) {
+	outbox.add(QueuedMessage{
+		Method:    method,
+		Message:   message,
+		QueuedAt:  time.Now().UTC(),
+		LastError: stripBotURL(err).Error(),
+		Article:   article,
+	})
+	printStatus("Queued the message for the next run", color.FgYellow)
+}
+
+// holdTelegram keeps a message until quiet hours end
+func holdTelegram(method string, message TelegramMessage, article *QueuedArticle, until time.Time) {
+	outbox.add(QueuedMessage{
+		Method:    method,
+		Message:   message,
+		QueuedAt:  time.Now().UTC(),
+		HoldUntil: until.UTC(),
+		Article:   article,
+	})
+}
+
+// flushOutbox resends the queued messages, keeping those that fail again
+// and those still held for quiet hours
+func flushOutbox(ctx context.Context, botToken string) {
+	queued := outbox.drain()
+	if len(queued) == 0 {
+		return
+	}
+	printStatus(fmt.Sprintf("Resending %d queued message(s)", len(queued)), color.FgYellow)
+
+	now := time.Now()
+	var remaining []QueuedMessage
+	for _, message := range queued {
+		if now.Before(message.HoldUntil) {
+			remaining = append(remaining, message)
+			continue
+		}
+		_, err := deliverTelegram(ctx, fmt.Sprintf(telegramMethodTemplate, botToken, message.Method), message.Message)
+		if err == nil {
+			recordTelegramDelivery()
+			if message.Article != nil {
+				recordDelivered(message.Article.Store, message.Article.Link)
+			}
+			continue
+		}
+		message.Attempts++
+		message.LastError = stripBotURL(err).Error()
+		if permanentTelegramError(err) || message.Attempts >= maxOutboxAttempts {
+			printError(fmt.Sprintf("Dropping queued message after %d attempt(s): %v", message.Attempts, stripBotURL(err)))
+			continue
+		}
+		remaining = append(remaining, message)
+	}
+
+	outbox.requeue(remaining)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// pentesterLandDataset is Pentester Land's published list of writeups
+const pentesterLandDataset = "pentester.land/writeups.json"
+
+// isPentesterLandFeed recognizes the writeups dataset
+func isPentesterLandFeed(feedURL string) bool {
+	return strings.Contains(feedURL, pentesterLandDataset)
+}
+
+// pentesterLandWriteup matches one entry of writeups.json
+type pentesterLandWriteup struct {
+	Links []struct {
+		Title string `json:"Title"`
+		Link  string `json:"Link"`
+	} `json:"Links"`
+	Authors         []string `json:"Authors"`
+	Programs        []string `json:"Programs"`
+	Bugs            []string `json:"Bugs"`
+	Bounty          string   `json:"Bounty"`
+	PublicationDate string   `json:"PublicationDate"`
+	AddedDate       string   `json:"AddedDate"`
+}
+
+// parsePentesterLandFeed turns every link of every entry into an item, with
+// authors, programs and bug types as categories so they match keywords
+func parsePentesterLandFeed(body []byte) ([]*gofeed.Item, error) {
+	var dataset struct {
+		Data []pentesterLandWriteup `json:"data"`
+	}
+	if err := json.Unmarshal(body, &dataset); err != nil {
+		return nil, fmt.Errorf("unmarshaling Pentester Land dataset: %w", err)
+	}
+
+	var items []*gofeed.Item
+	for _, writeup := range dataset.Data {
+		published := ""
+		// Dates are plain YYYY-MM-DD; the added date stands in for undated writeups
+		for _, date := range []string{writeup.PublicationDate, writeup.AddedDate} {
+			if t, err := time.Parse("2006-01-02", date); err == nil {
+				published = t.Format(time.RFC1123Z)
+				break
+			}
+		}
+
+		var categories []string
+		categories = append(categories, writeup.Bugs...)
+		categories = append(categories, writeup.Programs...)
+		categories = append(categories, writeup.Authors...)
+
+		// Bug types go into the description so keyword matching sees them
+		description := strings.Join(writeup.Bugs, ", ")
+		if len(writeup.Programs) > 0 {
+			description += " | " + strings.Join(writeup.Programs, ", ")
+		}
+
+		for _, link := range writeup.Links {
+			if link.Link == "" {
+				continue
+			}
+			item := &gofeed.Item{
+				Title:       link.Title,
+				Description: description,
+				Link:        link.Link,
+				Published:   published,
+				Categories:  categories,
+			}
+			if len(writeup.Authors) > 0 {
+				item.Author = &gofeed.Person{Name: strings.Join(writeup.Authors, ", ")}
+			}
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// pluginPrefix marks data.txt entries served by an external command
+// configured under plugins in hunter.yaml, e.g. "plugin:confluence"
+const (
+	pluginPrefix = "plugin:"
+	pluginScheme = "plugin://"
+)
+
+// PluginSettings is an external source: Command runs with Dir as working
+// directory and prints one JSON article per line on stdout, e.g.
+//
+//	{"title": "...", "link": "https://...", "published": "2025-01-02T15:04:05Z",
+//	 "description": "...", "author": "...", "tags": ["xss"]}
+//
+// Anything on stderr is passed through; a non-zero exit fails the source.
+type PluginSettings struct {
+	Command []string `yaml:"command"`
+	Dir     string   `yaml:"dir"`
+}
+
+// pluginArticle is the line format plugins print
+type pluginArticle struct {
+	Title       string   `json:"title"`
+	Link        string   `json:"link"`
+	Published   string   `json:"published"`
+	Description string   `json:"description"`
+	Author      string   `json:"author"`
+	Tags        []string `json:"tags"`
+}
+
+// expandPluginEntry checks the plugin is configured and returns its source URL
+func expandPluginEntry(entry string) ([]string, error) {
+	name := strings.TrimSpace(strings.TrimPrefix(entry, pluginPrefix))
+	if _, exists := settings.Plugins[name]; !exists {
+		return nil, fmt.Errorf("%s: no plugin %q under plugins in %s", entry, name, settingsFileName)
+	}
+	return []string{pluginScheme + name}, nil
+}
+
+// isPluginSource recognizes plugin source URLs
+func isPluginSource(feedURL string) bool {
+	return strings.HasPrefix(feedURL, pluginScheme)
+}
+
+// validatePluginSource checks a plugin:// source names a configured plugin
+// with a command
+func validatePluginSource(feedURL string) error {
+	name := strings.TrimPrefix(feedURL, pluginScheme)
+	plugin, exists := settings.Plugins[name]
+	if !exists {
+		return fmt.Errorf("%s: no plugin %q under plugins in %s", feedURL, name, settingsFileName)
+	}
+	if len(plugin.Command) == 0 {
+		return fmt.Errorf("%s: plugin %q has no command", feedURL, name)
+	}
+	return nil
+}
+
+// runPlugin executes the plugin and reads its articles; malformed lines are
+// reported and skipped so one bad record does not drop the rest
+func runPlugin(ctx context.Context, feedURL string) ([]*gofeed.Item, error) {
+	name := strings.TrimPrefix(feedURL, pluginScheme)
+	plugin, exists := settings.Plugins[name]
+	if !exists || len(plugin.Command) == 0 {
+		return nil, fmt.Errorf("plugin %q has no command", name)
+	}
+
+	cmd := exec.CommandContext(ctx, plugin.Command[0], plugin.Command[1:]...)
+	cmd.Dir = plugin.Dir
+	cmd.Stderr = os.Stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("running plugin %s: %w", name, err)
+	}
+
+	var items []*gofeed.Item
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var article pluginArticle
+		if err := json.Unmarshal(scanner.Bytes(), &article); err != nil {
+			printError(fmt.Sprintf("Plugin %s line %d: %v", name, line, err))
+			continue
+		}
+		if article.Link == "" {
+			printError(fmt.Sprintf("Plugin %s line %d: missing link", name, line))
+			continue
+		}
+
+		item := &gofeed.Item{
+			Title:       article.Title,
+			Description: article.Description,
+			Link:        article.Link,
+			Published:   article.Published,
+			Categories:  article.Tags,
+		}
+		if article.Author != "" {
+			item.Author = &gofeed.Person{Name: article.Author}
+		}
+		items = append(items, item)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading plugin %s output: %w", name, err)
+	}
+	return items, nil
+}
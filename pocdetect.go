@@ -0,0 +1,46 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Code shorter than this is usually an inline identifier, not a PoC
+const minPoCCodeLength = 40
+
+// pocCommandPattern matches shell commands that typically reproduce a finding
+var pocCommandPattern = regexp.MustCompile(`(?m)(?:^\s*|\$\s+)(?:curl|wget|sqlmap|nuclei|python3?|nc)\s+\S`)
+
+// detectPoC reports whether article content carries a proof of concept: a
+// code block, a reproducing shell command or a linked PoC repository
+func detectPoC(doc *goquery.Selection) bool {
+	found := false
+	doc.Find("pre, code").EachWithBreak(func(_ int, code *goquery.Selection) bool {
+		text := strings.TrimSpace(code.Text())
+		if len(text) >= minPoCCodeLength || strings.Contains(text, "\n") {
+			found = true
+		}
+		return !found
+	})
+	if found {
+		return true
+	}
+	if pocCommandPattern.MatchString(doc.Text()) {
+		return true
+	}
+
+	return len(pocLinks(doc)) > 0
+}
+
+// pocLinks returns the clone URLs of PoC repositories linked from the content,
+// which its extracted text no longer carries
+func pocLinks(doc *goquery.Selection) []string {
+	var links []string
+	doc.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+		href, _ := a.Attr("href")
+		links = append(links, href)
+	})
+	return findPoCLinks(strings.Join(links, " "))
+}
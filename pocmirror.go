@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+const (
+	pocMirrorTimeout = 2 * time.Minute
+	// defaultPoCMaxSizeMB skips repositories GitHub reports as larger than this
+	defaultPoCMaxSizeMB = 100
+)
+
+// githubReservedOwners are first path segments of github.com pages that are
+// not user or organization accounts, so never name a repository
+var githubReservedOwners = map[string]struct{}{
+	"about": {}, "apps": {}, "codespaces": {}, "collections": {}, "contact": {},
+	"customer-stories": {}, "enterprise": {}, "events": {}, "explore": {},
+	"features": {}, "issues": {}, "join": {}, "login": {}, "logout": {},
+	"marketplace": {}, "new": {}, "notifications": {}, "orgs": {},
+	"organizations": {}, "pricing": {}, "pulls": {}, "readme": {}, "search": {},
+	"security": {}, "settings": {}, "signup": {}, "site": {}, "sponsors": {},
+	"topics": {}, "trending": {}, "users": {},
+}
+
+// pocLinkPattern matches GitHub repositories and gists referenced from an article
+var pocLinkPattern = regexp.MustCompile(`https?://(gist\.github\.com|github\.com)/([A-Za-z0-9_.-]+)/([A-Za-z0-9_.-]+)`)
+
+// findPoCLinks returns the clone URLs of repositories and gists linked from the text
+func findPoCLinks(text string) []string {
+	seen := make(map[string]struct{})
+	var links []string
+	for _, match := range pocLinkPattern.FindAllStringSubmatch(text, -1) {
+		host, owner, name := match[1], match[2], strings.TrimSuffix(match[3], ".git")
+		// Skip GitHub's own pages such as github.com/features/...
+		if _, reserved := githubReservedOwners[strings.ToLower(owner)]; reserved && host == "github.com" {
+			continue
+		}
+		cloneURL := fmt.Sprintf("https://%s/%s/%s.git", host, owner, name)
+		if _, exists := seen[cloneURL]; exists {
+			continue
+		}
+		seen[cloneURL] = struct{}{}
+		links = append(links, cloneURL)
+	}
+	return links
+}
+
+// mirrorPoCs snapshots every linked PoC into the mirror directory, creating a bare
+// mirror on first sight and refreshing it afterwards so deleted upstreams survive
+func mirrorPoCs(ctx context.Context, text string) {
+	if settings.PoCMirror.Dir == "" {
+		return
+	}
+
+	for _, cloneURL := range findPoCLinks(text) {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := mirrorRepository(ctx, cloneURL, settings.PoCMirror.Dir); err != nil {
+			printError(fmt.Sprintf("Mirroring %s: %v", cloneURL, err))
+			continue
+		}
+		printStatus(fmt.Sprintf("Mirrored PoC %s", cloneURL), color.FgBlue)
+	}
+}
+
+// mirrorRepository keeps only the latest commit of each branch, so a PoC that
+// ships large history or binaries cannot fill the mirror directory
+func mirrorRepository(ctx context.Context, cloneURL, dir string) error {
+	trimmed := strings.TrimPrefix(cloneURL, "https://")
+	target := filepath.Join(dir, filepath.FromSlash(trimmed))
+
+	ctx, cancel := context.WithTimeout(ctx, pocMirrorTimeout)
+	defer cancel()
+
+	if host, path, _ := strings.Cut(trimmed, "/"); host == "github.com" {
+		maxMB := settings.PoCMirror.MaxSizeMB
+		if maxMB == 0 {
+			maxMB = defaultPoCMaxSizeMB
+		}
+		sizeKB, err := githubRepositorySize(ctx, strings.TrimSuffix(path, ".git"))
+		if err != nil {
+			return err
+		}
+		if maxMB > 0 && sizeKB > maxMB*1024 {
+			return fmt.Errorf("repository is %d MB, over the %d MB limit", sizeKB/1024, maxMB)
+		}
+	}
+
+	var cmd *exec.Cmd
+	if _, err := os.Stat(target); err == nil {
+		cmd = exec.CommandContext(ctx, "git", "--git-dir", target, "fetch", "--depth", "1", "--prune", "--quiet", "origin")
+	} else {
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return fmt.Errorf("creating %s: %w", filepath.Dir(target), err)
+		}
+		cmd = exec.CommandContext(ctx, "git", "clone", "--mirror", "--depth", "1", "--quiet", cloneURL, target)
+	}
+
+	// Never prompt for credentials on private or deleted repositories
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	if settings.PoCMirror.Push != "" {
+		return pushMirror(ctx, target, settings.PoCMirror.Push, trimmed)
+	}
+	return nil
+}
+
+// githubRepositorySize asks the GitHub API for the size of owner/name in KB
+func githubRepositorySize(ctx context.Context, repository string) (int, error) {
+	apiURL := githubAPIBase + repository
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		return 0, fmt.Errorf("creating request: %w", err)
+	}
+	setUserAgent(req)
+	applyFeedAuth(req, apiURL)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("fetching repository size: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, &HTTPError{StatusCode: resp.StatusCode}
+	}
+	var repo struct {
+		Size int `json:"size"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&repo); err != nil {
+		return 0, fmt.Errorf("decoding repository: %w", err)
+	}
+	return repo.Size, nil
+}
+
+// pushMirror copies the mirror into a private backup remote under a branch
+// namespace derived from the repository path
+func pushMirror(ctx context.Context, gitDir, remote, name string) error {
+	namespace := strings.NewReplacer("/", "_", ".git", "").Replace(name)
+	cmd := exec.CommandContext(ctx, "git", "--git-dir", gitDir, "push", "--force", remote,
+		fmt.Sprintf("+refs/heads/*:refs/heads/%s/*", namespace))
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("pushing to backup: %w: %s", err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
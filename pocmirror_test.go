@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestFindPoCLinks(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"repository", "PoC at https://github.com/alice/cve-2024-1234 now",
+			[]string{"https://github.com/alice/cve-2024-1234.git"}},
+		{"clone URL", "git clone https://github.com/alice/exploit.git",
+			[]string{"https://github.com/alice/exploit.git"}},
+		{"gist", "see https://gist.github.com/bob/0123abcd",
+			[]string{"https://gist.github.com/bob/0123abcd.git"}},
+		{"duplicates", "https://github.com/alice/poc and https://github.com/alice/poc/blob/main/x.py",
+			[]string{"https://github.com/alice/poc.git"}},
+		{"GitHub pages", "https://github.com/orgs/acme/people https://github.com/settings/tokens " +
+			"https://github.com/marketplace/actions https://github.com/sponsors/alice " +
+			"https://github.com/topics/ssrf https://github.com/collections/security " +
+			"https://github.com/login/oauth https://github.com/Features/actions", nil},
+		{"no links", "nothing to mirror here", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := findPoCLinks(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("findPoCLinks() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExtractedPoCLinks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><body><main>
+<p>The full exploit is <a href="https://github.com/alice/cve-2024-1234">on GitHub</a>.</p>
+<p>Read the <a href="https://github.com/topics/ssrf">topic</a> too.</p>
+</main></body></html>`)
+	}))
+	defer server.Close()
+	useSettings(t, Settings{})
+
+	extracted, err := extractContent(context.Background(), server.URL, true)
+	if err != nil {
+		t.Fatalf("extractContent() error = %v", err)
+	}
+	want := []string{"https://github.com/alice/cve-2024-1234.git"}
+	if !reflect.DeepEqual(extracted.PoCs, want) {
+		t.Errorf("PoCs = %v, want %v", extracted.PoCs, want)
+	}
+	// The link text alone names no repository
+	if got := findPoCLinks(extracted.Body); got != nil {
+		t.Errorf("findPoCLinks(Body) = %v, want nil", got)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/pprof"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// defaultPprofAddr keeps the profiler off the network unless asked otherwise
+const defaultPprofAddr = "127.0.0.1:6060"
+
+// PprofSettings exposes net/http/pprof in serve mode for diagnosing memory
+// growth and goroutine leaks. It is off by default and listens on localhost
+// unless Addr says otherwise.
+type PprofSettings struct {
+	Enabled bool   `yaml:"enabled"`
+	Addr    string `yaml:"addr"`
+}
+
+// startPprof serves /debug/pprof/ on its own listener and returns a function
+// that shuts it down
+func startPprof() func() {
+	addr := firstNonEmpty(settings.Pprof.Addr, defaultPprofAddr)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			printError(fmt.Sprintf("pprof server on %s: %v", addr, err))
+		}
+	}()
+	printStatus(fmt.Sprintf("Serving pprof on %s/debug/pprof/", addr), color.FgCyan)
+	return func() { server.Close() }
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Profile is a named set of feeds, keywords and destination from hunter.yaml.
+// The unnamed default profile uses data.txt, the built-in keywords and
+// TELEGRAM_CHANNEL_ID.
+type Profile struct {
+	Name      string            `yaml:"-"`
+	Feeds     string            `yaml:"feeds"`
+	Keywords  map[string]string `yaml:"keywords"`
+	ChannelID string            `yaml:"channel_id"`
+
+	// Destinations replaces the single ChannelID with several chats
+	Destinations []Destination `yaml:"destinations"`
+
+	// Languages allowlists article languages by ISO 639-1 code (en, pt, ...);
+	// it defaults to the global languages setting
+	Languages []string `yaml:"languages"`
+
+	// Schedule is when "serve" runs the profile, overriding the global one
+	Schedule string `yaml:"schedule"`
+}
+
+// selectProfiles resolves --profile against the configured profiles, filling
+// in defaults so every returned profile is runnable
+func selectProfiles(configured map[string]Profile, name, defaultChannelID string) ([]Profile, error) {
+	if len(configured) == 0 {
+		if name != "" && name != "all" {
+			return nil, fmt.Errorf("profile %q requested but %s defines no profiles", name, settingsFileName)
+		}
+		profile := Profile{Feeds: urlsFileName, Keywords: applyCategories(keywords), ChannelID: defaultChannelID, Languages: settings.Languages}
+		profile.Destinations = profileDestinations(profile, false)
+		return []Profile{profile}, nil
+	}
+
+	var names []string
+	if name == "" || name == "all" {
+		for n := range configured {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+	} else {
+		if _, exists := configured[name]; !exists {
+			return nil, fmt.Errorf("unknown profile %q", name)
+		}
+		names = []string{name}
+	}
+
+	profiles := make([]Profile, 0, len(names))
+	for _, n := range names {
+		profile := configured[n]
+		profile.Name = n
+		if profile.Feeds == "" {
+			profile.Feeds = urlsFileName
+		}
+		if len(profile.Languages) == 0 {
+			profile.Languages = settings.Languages
+		}
+		if len(profile.Keywords) == 0 {
+			profile.Keywords = keywords
+		}
+		profile.Keywords = applyCategories(profile.Keywords)
+		ownChannel := profile.ChannelID != ""
+		if !ownChannel {
+			profile.ChannelID = defaultChannelID
+		}
+		profile.Destinations = profileDestinations(profile, ownChannel)
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
+
+// commandProfiles resolves --profile for subcommands that only read or edit
+// state files and so need no channel
+func commandProfiles() ([]Profile, error) {
+	return selectProfiles(settings.Profiles, profileName, "")
+}
+
+// profileDestinations falls back from the profile's destinations to its own
+// channel_id, then to the global destinations, and from there to the default
+// channel. ownChannel reports whether the profile set channel_id itself.
+func profileDestinations(p Profile, ownChannel bool) []Destination {
+	if len(p.Destinations) > 0 {
+		return p.Destinations
+	}
+	if !ownChannel && len(settings.Destinations) > 0 {
+		return settings.Destinations
+	}
+	return []Destination{{ChannelID: p.ChannelID}}
+}
+
+// threadFor returns the message thread for a keyword or category, falling back
+// to the built-in general thread when neither defines one
+func (p Profile) threadFor(keyword string) string {
+	if thread, exists := p.configuredThread(keyword); exists {
+		return thread
+	}
+	return keywords["general"]
+}
+
+// configuredThread looks up the thread configured for a keyword, category,
+// program or severity, without falling back to the general thread
+func (p Profile) configuredThread(keyword string) (string, bool) {
+	if thread, exists := p.Keywords[keyword]; exists {
+		return thread, true
+	}
+	if thread, exists := categoryThreads[keyword]; exists && thread != "" {
+		return thread, true
+	}
+	if thread := programThread(keyword); thread != "" {
+		return thread, true
+	}
+	if thread := severityThread(keyword); thread != "" {
+		return thread, true
+	}
+	return "", false
+}
+
+// label prefixes run messages with the profile name
+func (p Profile) label() string {
+	if p.Name == "" {
+		return ""
+	}
+	return "[" + p.Name + "] "
+}
+
+// stateFile namespaces a state file by profile and then by environment,
+// e.g. found-url.web.staging.txt
+func (p Profile) stateFile(name string) string {
+	if p.Name == "" {
+		return stateFileName(name)
+	}
+	ext := filepath.Ext(name)
+	return stateFileName(strings.TrimSuffix(name, ext) + "." + p.Name + ext)
+}
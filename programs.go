@@ -0,0 +1,46 @@
+package main
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Program is a bug bounty program or vendor recognized in article text by its
+// name or aliases (plain, matched as whole words, or /regex/). Articles about a
+// program with a thread are also posted there, and destinations can filter
+// and route on the program name like on any tag.
+type Program struct {
+	Aliases []string `yaml:"aliases"`
+	Thread  string   `yaml:"thread"`
+}
+
+// matchesProgramTerm matches a program name or alias as a whole word
+func matchesProgramTerm(text, term string) bool {
+	if pattern, isRegex := regexKeyword(term); isRegex {
+		re := cachedPattern(pattern, compileKeyword)
+		return re != nil && re.MatchString(text)
+	}
+	re := cachedPattern(plainKeywordPattern(term, true, false), regexp.Compile)
+	return re != nil && re.MatchString(text)
+}
+
+// extractPrograms returns the configured programs an article mentions
+func extractPrograms(text string) []string {
+	var programs []string
+	for name, program := range settings.Programs {
+		for _, term := range append([]string{name}, program.Aliases...) {
+			if strings.TrimSpace(term) != "" && matchesProgramTerm(text, term) {
+				programs = append(programs, name)
+				break
+			}
+		}
+	}
+	sort.Strings(programs)
+	return programs
+}
+
+// programThread returns the thread configured for a program, if any
+func programThread(name string) string {
+	return settings.Programs[name].Thread
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// configureProxy routes every outbound request, feeds and Telegram alike,
+// through proxyForRequest. http://, https://, socks5:// and socks5h:// proxy
+// URLs are supported.
+func configureProxy() error {
+	if settings.Proxy != "" {
+		if _, err := parseProxyURL(settings.Proxy); err != nil {
+			return err
+		}
+	}
+	if settings.Telegram.Proxy != "" {
+		if _, err := parseProxyURL(settings.Telegram.Proxy); err != nil {
+			return fmt.Errorf("telegram: %w", err)
+		}
+	}
+	for feedURL, options := range settings.Feeds {
+		if options.Proxy == "" {
+			continue
+		}
+		if _, err := parseProxyURL(options.Proxy); err != nil {
+			return fmt.Errorf("feeds.%s: %w", feedURL, err)
+		}
+	}
+
+	if transport, ok := http.DefaultTransport.(*http.Transport); ok {
+		transport.Proxy = proxyForRequest
+	}
+	return nil
+}
+
+// proxyForRequest prefers the Telegram proxy for Bot API calls and the feed's
+// proxy for fetches, then Tor for fetches, then the global proxy, then the
+// standard HTTP_PROXY/HTTPS_PROXY/NO_PROXY variables
+func proxyForRequest(req *http.Request) (*url.URL, error) {
+	if settings.Telegram.Proxy != "" && req.URL.Hostname() == telegramAPIHost {
+		return parseProxyURL(settings.Telegram.Proxy)
+	}
+	if proxy := feedOptions(req.URL.String()).Proxy; proxy != "" {
+		return parseProxyURL(proxy)
+	}
+	if settings.Tor.Enabled && req.URL.Hostname() != telegramAPIHost {
+		return torProxyFor(req.URL.Hostname()), nil
+	}
+	if settings.Proxy != "" {
+		return parseProxyURL(settings.Proxy)
+	}
+	return http.ProxyFromEnvironment(req)
+}
+
+// TorSettings routes feed and article fetches (not Telegram) through Tor
+type TorSettings struct {
+	Enabled bool   `yaml:"enabled"`
+	Address string `yaml:"address"`
+	// Isolate gives every domain its own circuit so sources cannot correlate
+	// requests; it relies on Tor's default IsolateSOCKSAuth
+	Isolate bool `yaml:"isolate"`
+}
+
+const (
+	defaultTorAddress = "127.0.0.1:9050"
+	telegramAPIHost   = "api.telegram.org"
+)
+
+// torProxyFor builds the Tor SOCKS URL for a host. socks5h keeps DNS inside
+// Tor, and with isolation the host doubles as SOCKS username so Tor opens a
+// separate circuit per domain.
+func torProxyFor(host string) *url.URL {
+	address := settings.Tor.Address
+	if address == "" {
+		address = defaultTorAddress
+	}
+	proxy := &url.URL{Scheme: "socks5h", Host: address}
+	if settings.Tor.Isolate && host != "" {
+		proxy.User = url.UserPassword(host, "isolate")
+	}
+	return proxy
+}
+
+func parseProxyURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy %q: %w", raw, err)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+		return u, nil
+	default:
+		return nil, fmt.Errorf("unsupported proxy scheme %q in %q", u.Scheme, raw)
+	}
+}
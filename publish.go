@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const defaultPublishLimit = 1000
+
+// PublishSettings writes matched articles to a writeups.xyz-style index.json
+// that other instances can subscribe to like any writeups.xyz feed
+type PublishSettings struct {
+	Path  string `yaml:"path"`
+	Limit int    `yaml:"limit"`
+}
+
+// toWriteupsXYZItem converts a matched article into the writeups.xyz schema,
+// using the matched keywords as vulnerability titles
+func toWriteupsXYZItem(article *Article) writeupsXYZItem {
+	item := writeupsXYZItem{
+		Title:         article.Title,
+		Description:   article.Description,
+		Link:          cleanURL(article.Link),
+		PublishedDate: article.Published,
+	}
+	if article.Author != "" {
+		for _, name := range strings.Split(article.Author, ", ") {
+			item.Authors = append(item.Authors, writeupsXYZAuthor{Name: name})
+		}
+	}
+	for _, keyword := range article.Keywords {
+		item.Vulnerabilities = append(item.Vulnerabilities, writeupsXYZTag{Title: keyword})
+	}
+	return item
+}
+
+// publishArticles merges new articles into the published index, newest first,
+// keeping at most the configured number of entries
+func publishArticles(cfg PublishSettings, articles []*Article) error {
+	if cfg.Path == "" || len(articles) == 0 {
+		return nil
+	}
+	limit := cfg.Limit
+	if limit <= 0 {
+		limit = defaultPublishLimit
+	}
+
+	var existing []writeupsXYZItem
+	data, err := os.ReadFile(cfg.Path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading %s: %w", cfg.Path, err)
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &existing); err != nil {
+			return fmt.Errorf("parsing %s: %w", cfg.Path, err)
+		}
+	}
+
+	seen := make(map[string]struct{})
+	var index []writeupsXYZItem
+	for i := len(articles) - 1; i >= 0; i-- {
+		item := toWriteupsXYZItem(articles[i])
+		if _, exists := seen[item.Link]; exists {
+			continue
+		}
+		seen[item.Link] = struct{}{}
+		index = append(index, item)
+	}
+	for _, item := range existing {
+		if _, exists := seen[item.Link]; exists {
+			continue
+		}
+		seen[item.Link] = struct{}{}
+		index = append(index, item)
+	}
+	if len(index) > limit {
+		index = index[:limit]
+	}
+
+	out, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding index: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(cfg.Path), 0755); err != nil {
+		return fmt.Errorf("creating %s: %w", filepath.Dir(cfg.Path), err)
+	}
+
+	// Write atomically so readers never see a half-written index
+	tmp := cfg.Path + ".tmp"
+	if err := os.WriteFile(tmp, out, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", tmp, err)
+	}
+	if err := os.Rename(tmp, cfg.Path); err != nil {
+		return fmt.Errorf("renaming %s: %w", tmp, err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+// quietTimeLayout is how quiet hour bounds are written, e.g. "22:30"
+const quietTimeLayout = "15:04"
+
+// QuietHoursSettings mutes notifications overnight: between Start and End
+// (local time, wrapping past midnight) messages are sent silently, or held in
+// the outbox until the first run after End when Hold is set
+type QuietHoursSettings struct {
+	Start string `yaml:"start"`
+	End   string `yaml:"end"`
+	Hold  bool   `yaml:"hold"`
+}
+
+// parseQuietTime returns the minutes past midnight of an HH:MM bound
+func parseQuietTime(value string) (int, error) {
+	t, err := time.Parse(quietTimeLayout, value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected HH:MM", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// quietUntil reports whether now falls within quiet hours and, if so, when
+// they end
+func quietUntil(now time.Time) (time.Time, bool) {
+	quiet := settings.QuietHours
+	if quiet.Start == "" || quiet.End == "" {
+		return time.Time{}, false
+	}
+	start, err := parseQuietTime(quiet.Start)
+	if err != nil {
+		return time.Time{}, false
+	}
+	end, err := parseQuietTime(quiet.End)
+	if err != nil || start == end {
+		return time.Time{}, false
+	}
+
+	minute := now.Hour()*60 + now.Minute()
+	var inside bool
+	if start < end {
+		inside = minute >= start && minute < end
+	} else {
+		inside = minute >= start || minute < end
+	}
+	if !inside {
+		return time.Time{}, false
+	}
+
+	y, m, d := now.Date()
+	until := time.Date(y, m, d, end/60, end%60, 0, 0, now.Location())
+	if !until.After(now) {
+		until = until.AddDate(0, 0, 1)
+	}
+	return until, true
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseQuietTime(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    int
+		wantErr bool
+	}{
+		{"00:00", 0, false},
+		{"07:30", 450, false},
+		{"23:59", 1439, false},
+		{"24:00", 0, true},
+		{"7:30pm", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseQuietTime(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseQuietTime(%q) error = %v, want error %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseQuietTime(%q) = %d, want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestQuietUntil(t *testing.T) {
+	day := func(d, h, m int) time.Time {
+		return time.Date(2025, 1, d, h, m, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name       string
+		start, end string
+		now        time.Time
+		want       time.Time
+		wantQuiet  bool
+	}{
+		{"not configured", "", "", day(15, 23, 0), time.Time{}, false},
+		{"invalid bound", "22:00", "7am", day(15, 23, 0), time.Time{}, false},
+		{"start equals end", "22:00", "22:00", day(15, 22, 0), time.Time{}, false},
+		{"same day inside", "12:00", "14:00", day(15, 13, 15), day(15, 14, 0), true},
+		{"same day at start", "12:00", "14:00", day(15, 12, 0), day(15, 14, 0), true},
+		{"same day at end", "12:00", "14:00", day(15, 14, 0), time.Time{}, false},
+		{"same day before", "12:00", "14:00", day(15, 11, 59), time.Time{}, false},
+		{"overnight before midnight", "22:00", "07:00", day(15, 23, 30), day(16, 7, 0), true},
+		{"overnight after midnight", "22:00", "07:00", day(16, 3, 0), day(16, 7, 0), true},
+		{"overnight daytime", "22:00", "07:00", day(15, 12, 0), time.Time{}, false},
+		{"overnight at end", "22:00", "07:00", day(16, 7, 0), time.Time{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useSettings(t, Settings{QuietHours: QuietHoursSettings{Start: tt.start, End: tt.end}})
+
+			got, quiet := quietUntil(tt.now)
+			if quiet != tt.wantQuiet {
+				t.Fatalf("quietUntil(%v) quiet = %v, want %v", tt.now, quiet, tt.wantQuiet)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("quietUntil(%v) = %v, want %v", tt.now, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// redditPrefix marks data.txt shorthand for subreddits, e.g.
+// "reddit:netsec,bugbounty,websecurity"
+const (
+	redditPrefix  = "reddit:"
+	redditBaseURL = "https://www.reddit.com"
+)
+
+// expandRedditEntry turns a reddit: line into one JSON listing URL per subreddit
+func expandRedditEntry(entry string) ([]string, error) {
+	var feeds []string
+	for _, name := range strings.Split(strings.TrimPrefix(entry, redditPrefix), ",") {
+		name = strings.TrimPrefix(strings.TrimSpace(name), "r/")
+		if name == "" {
+			continue
+		}
+		if strings.ContainsAny(name, "/?# ") {
+			return nil, fmt.Errorf("malformed reddit entry: %q", entry)
+		}
+		feeds = append(feeds, fmt.Sprintf("%s/r/%s/new.json?limit=50&raw_json=1", redditBaseURL, name))
+	}
+	if len(feeds) == 0 {
+		return nil, fmt.Errorf("reddit entry %q names no subreddits", entry)
+	}
+	return feeds, nil
+}
+
+// isRedditListing recognizes Reddit's JSON listing endpoints
+func isRedditListing(feedURL string) bool {
+	u, err := url.Parse(feedURL)
+	if err != nil {
+		return false
+	}
+	domain := getDomain(feedURL)
+	return (domain == "reddit.com" || strings.HasSuffix(domain, ".reddit.com")) && strings.HasSuffix(u.Path, ".json")
+}
+
+// redditListing is the subset of a /r/<sub>/new.json answer we use
+type redditListing struct {
+	Data struct {
+		Children []struct {
+			Data redditPost `json:"data"`
+		} `json:"children"`
+	} `json:"data"`
+}
+
+type redditPost struct {
+	Title      string  `json:"title"`
+	Selftext   string  `json:"selftext"`
+	URL        string  `json:"url"`
+	Permalink  string  `json:"permalink"`
+	Author     string  `json:"author"`
+	CreatedUTC float64 `json:"created_utc"`
+	IsSelf     bool    `json:"is_self"`
+	Flair      string  `json:"link_flair_text"`
+	Stickied   bool    `json:"stickied"`
+}
+
+// parseRedditListing maps posts to items linking to the outbound writeup
+// rather than the comments page; self posts keep their permalink
+func parseRedditListing(body []byte) ([]*gofeed.Item, error) {
+	var listing redditListing
+	if err := json.Unmarshal(body, &listing); err != nil {
+		return nil, fmt.Errorf("unmarshaling Reddit listing: %w", err)
+	}
+
+	var items []*gofeed.Item
+	for _, child := range listing.Data.Children {
+		post := child.Data
+		// Pinned weekly threads are not writeups
+		if post.Stickied {
+			continue
+		}
+
+		link := post.URL
+		if post.IsSelf || link == "" || strings.HasPrefix(link, "/") {
+			link = redditBaseURL + post.Permalink
+		}
+
+		item := &gofeed.Item{
+			Title:       post.Title,
+			Description: post.Selftext,
+			Link:        link,
+			Published:   time.Unix(int64(post.CreatedUTC), 0).UTC().Format(time.RFC1123Z),
+		}
+		if post.Author != "" {
+			item.Author = &gofeed.Person{Name: "u/" + post.Author}
+		}
+		if post.Flair != "" {
+			item.Categories = []string{post.Flair}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
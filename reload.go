@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/fatih/color"
+)
+
+// watchReload returns a channel that receives on SIGHUP so the feed list
+// can be re-read between feeds without interrupting the current cycle
+func watchReload() chan os.Signal {
+	reload := make(chan os.Signal, 1)
+	signal.Notify(reload, syscall.SIGHUP)
+	return reload
+}
+
+// reloadFeedList re-reads the feed list and appends feeds that are not yet queued.
+// Feeds removed from the file are left in place for the current cycle.
+func reloadFeedList(filename string, queued []string) []string {
+	entries, err := readURLs(filename)
+	if err != nil {
+		printError(fmt.Sprintf("Reloading feed list: %v", err))
+		return queued
+	}
+
+	known := make(map[string]struct{}, len(queued))
+	for _, u := range queued {
+		known[u] = struct{}{}
+	}
+
+	added := 0
+	for _, u := range expandFeedList(entries) {
+		if _, exists := known[u]; exists {
+			continue
+		}
+		queued = append(queued, u)
+		added++
+	}
+
+	printStatus(fmt.Sprintf("Reloaded %s: %d new feeds queued", filename, added), color.FgYellow)
+	return queued
+}
@@ -0,0 +1,15 @@
+package main
+
+// Route sends a tag to an explicit chat and, for forum supergroups, a topic.
+// Without a thread the message goes to the chat itself, so plain groups and
+// channels without topics work too.
+type Route struct {
+	ChatID string `yaml:"chat_id"`
+	Thread string `yaml:"message_thread_id"`
+}
+
+// routeFor looks up the routing table entry of a keyword, category or "general"
+func routeFor(tag string) (Route, bool) {
+	route, exists := settings.Routes[tag]
+	return route, exists
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"context"
+	"testing"
+)
+
+func TestRouteFor(t *testing.T) {
+	useSettings(t, Settings{Routes: map[string]Route{
+		"xss":     {ChatID: "-100", Thread: "7"},
+		"general": {ChatID: "-200"},
+	}})
+
+	tests := []struct {
+		tag       string
+		want      Route
+		wantFound bool
+	}{
+		{"xss", Route{ChatID: "-100", Thread: "7"}, true},
+		{"general", Route{ChatID: "-200"}, true},
+		{"idor", Route{}, false},
+	}
+	for _, tt := range tests {
+		got, found := routeFor(tt.tag)
+		if got != tt.want || found != tt.wantFound {
+			t.Errorf("routeFor(%q) = %+v, %v, want %+v, %v", tt.tag, got, found, tt.want, tt.wantFound)
+		}
+	}
+}
+
+func TestDestinationAccepts(t *testing.T) {
+	filtered := Destination{Keywords: []string{"XSS", "idor"}}
+
+	tests := []struct {
+		destination Destination
+		tag         string
+		want        bool
+	}{
+		{Destination{}, "ssrf", true},
+		{filtered, "xss", true},
+		{filtered, "idor", true},
+		{filtered, "ssrf", false},
+		{filtered, "general", true},
+		{filtered, slowFeedsTag, true},
+	}
+	for _, tt := range tests {
+		if got := tt.destination.accepts(tt.tag); got != tt.want {
+			t.Errorf("%v.accepts(%q) = %v, want %v", tt.destination.Keywords, tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestDestinationThreadFor(t *testing.T) {
+	useSettings(t, Settings{Categories: map[string]Category{
+		"web": {Thread: "30", Terms: []string{"web"}},
+		"api": {Terms: []string{"graphql"}},
+	}})
+
+	profile := Profile{Keywords: map[string]string{"xss": "10", "ssrf": "11"}}
+	destination := Destination{ChannelID: "-100", Threads: map[string]string{"xss": "20"}}
+
+	tests := []struct {
+		tag, want string
+	}{
+		// The destination's own mapping wins over the profile's
+		{"xss", "20"},
+		{"ssrf", "11"},
+		{"web", "30"},
+		// A category without a thread falls back to the general one
+		{"api", keywords["general"]},
+		{"unknown", keywords["general"]},
+	}
+	for _, tt := range tests {
+		if got := destination.threadFor(context.Background(), profile, "", tt.tag); got != tt.want {
+			t.Errorf("threadFor(%q) = %q, want %q", tt.tag, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// rssHubPrefix marks data.txt shorthand for RSSHub routes, e.g.
+// "rsshub:/wechat/mp/msgalbum/xxx" reads that route from rsshub.base_url
+const (
+	rssHubPrefix     = "rsshub:"
+	defaultRSSHubURL = "https://rsshub.app"
+)
+
+// RSSHubSettings points at the RSSHub instance; self-hosting is recommended
+// since the public one rate limits heavily. AccessKey is sent as ?key= for
+// instances that require it.
+type RSSHubSettings struct {
+	BaseURL   string `yaml:"base_url"`
+	AccessKey string `yaml:"access_key"`
+}
+
+// expandRSSHubEntry turns an rsshub: route into a feed URL on the instance
+func expandRSSHubEntry(entry string) ([]string, error) {
+	route := strings.TrimSpace(strings.TrimPrefix(entry, rssHubPrefix))
+	if route == "" || strings.Contains(route, "://") {
+		return nil, fmt.Errorf("malformed rsshub entry: %q", entry)
+	}
+	if !strings.HasPrefix(route, "/") {
+		route = "/" + route
+	}
+
+	base := strings.TrimSuffix(settings.RSSHub.BaseURL, "/")
+	if base == "" {
+		base = defaultRSSHubURL
+	}
+	feedURL := base + route
+	if settings.RSSHub.AccessKey != "" {
+		separator := "?"
+		if strings.Contains(route, "?") {
+			separator = "&"
+		}
+		feedURL += separator + "key=" + settings.RSSHub.AccessKey
+	}
+	return []string{feedURL}, nil
+}
@@ -53,7 +53,7 @@ while true; do
     cd "$REPO_DIR" || exit 1
     
     # Run the application
-    if go run main.go; then
+    if write-scraper; then
         echo "$(date) - writeup-hunter completed successfully"
     else
         echo "$(date) - ERROR: writeup-hunter failed"
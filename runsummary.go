@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// RunSummary is the machine-readable report of a run, written with
+// --summary for CI wrappers and job summaries
+type RunSummary struct {
+	Started         time.Time        `json:"started"`
+	DurationSeconds float64          `json:"duration_seconds"`
+	Profiles        []ProfileSummary `json:"profiles"`
+}
+
+// ProfileSummary reports one profile's feeds and matches
+type ProfileSummary struct {
+	Name            string         `json:"name,omitempty"`
+	Feeds           int            `json:"feeds"`
+	FeedsProcessed  int            `json:"feeds_processed"`
+	FailedFeeds     int            `json:"failed_feeds"`
+	Failures        []FeedFailure  `json:"failures,omitempty"`
+	Articles        int            `json:"articles"`
+	Matched         map[string]int `json:"matched,omitempty"`
+	DurationSeconds float64        `json:"duration_seconds"`
+	// Error is set when the profile could not run at all
+	Error string `json:"error,omitempty"`
+}
+
+// FeedFailure is a feed that failed and why
+type FeedFailure struct {
+	URL    string `json:"url"`
+	Reason string `json:"reason"`
+}
+
+// failed records a feed failure
+func (s *ProfileSummary) failed(url string, err error) {
+	s.Failures = append(s.Failures, FeedFailure{URL: url, Reason: err.Error()})
+}
+
+// recovered drops a failure whose retry succeeded
+func (s *ProfileSummary) recovered(url string) {
+	for i, failure := range s.Failures {
+		if failure.URL == url {
+			s.Failures = append(s.Failures[:i], s.Failures[i+1:]...)
+			return
+		}
+	}
+}
+
+// matched counts a notified article under a keyword or category
+func (s *ProfileSummary) matched(keyword string) {
+	if s.Matched == nil {
+		s.Matched = make(map[string]int)
+	}
+	s.Matched[keyword]++
+}
+
+// writeRunSummary writes the report as JSON to a file, or to stdout for "-"
+func writeRunSummary(path string, summary RunSummary) error {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding run summary: %w", err)
+	}
+	if path == "-" {
+		_, err := os.Stdout.Write(append(data, '\n'))
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// schedule answers when a daemon run is next due
+type schedule interface {
+	next(after time.Time) time.Time
+}
+
+// everySchedule runs at a fixed interval, e.g. "@every 30m"
+type everySchedule time.Duration
+
+func (s everySchedule) next(after time.Time) time.Time {
+	return after.Add(time.Duration(s))
+}
+
+// cronSchedule is a standard five-field cron expression: minute, hour, day
+// of month, month and day of week (0 or 7 is Sunday)
+type cronSchedule struct {
+	minute, hour, dom, month, dow []bool
+	// Like cron, a restricted day of month and day of week match either
+	domAny, dowAny bool
+}
+
+var scheduleAliases = map[string]string{
+	"@hourly":   "0 * * * *",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@weekly":   "0 0 * * 0",
+	"@monthly":  "0 0 1 * *",
+}
+
+// parseSchedule accepts cron expressions, @hourly-style aliases and
+// "@every <duration>"
+func parseSchedule(spec string) (schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if interval, found := strings.CutPrefix(spec, "@every "); found {
+		d, err := time.ParseDuration(strings.TrimSpace(interval))
+		if err != nil || d < time.Minute {
+			return nil, fmt.Errorf("invalid schedule %q: @every needs a duration of at least 1m", spec)
+		}
+		return everySchedule(d), nil
+	}
+	if alias, exists := scheduleAliases[spec]; exists {
+		spec = alias
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid schedule %q: expected 5 cron fields", spec)
+	}
+	bounds := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	var sets [5][]bool
+	for i, field := range fields {
+		set, err := parseCronField(field, bounds[i][0], bounds[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %w", spec, err)
+		}
+		sets[i] = set
+	}
+	// Sunday is both 0 and 7
+	sets[4][0] = sets[4][0] || sets[4][7]
+
+	return cronSchedule{
+		minute: sets[0], hour: sets[1], dom: sets[2], month: sets[3], dow: sets[4],
+		domAny: fields[2] == "*", dowAny: fields[4] == "*",
+	}, nil
+}
+
+// parseCronField expands "*", "*/n", "a", "a-b", "a-b/n" and comma lists
+func parseCronField(field string, min, max int) ([]bool, error) {
+	set := make([]bool, max+1)
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			if step, err = strconv.Atoi(stepPart); err != nil || step < 1 {
+				return nil, fmt.Errorf("bad step in %q", part)
+			}
+		}
+
+		lo, hi := min, max
+		if rangePart != "*" {
+			from, to, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if lo, err = strconv.Atoi(from); err != nil {
+				return nil, fmt.Errorf("bad value in %q", part)
+			}
+			hi = lo
+			if isRange {
+				if hi, err = strconv.Atoi(to); err != nil {
+					return nil, fmt.Errorf("bad value in %q", part)
+				}
+			} else if hasStep {
+				hi = max
+			}
+		}
+		if lo < min || hi > max || lo > hi {
+			return nil, fmt.Errorf("%q is outside %d-%d", part, min, max)
+		}
+		for v := lo; v <= hi; v += step {
+			set[v] = true
+		}
+	}
+	return set, nil
+}
+
+// next scans forward minute by minute; a year covers every valid expression
+func (s cronSchedule) next(after time.Time) time.Time {
+	t := after.Truncate(time.Minute).Add(time.Minute)
+	for end := t.AddDate(1, 0, 0); t.Before(end); t = t.Add(time.Minute) {
+		if s.minute[t.Minute()] && s.hour[t.Hour()] && s.month[int(t.Month())] && s.dayMatches(t) {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func (s cronSchedule) dayMatches(t time.Time) bool {
+	dom, dow := s.dom[t.Day()], s.dow[int(t.Weekday())]
+	switch {
+	case s.domAny && s.dowAny:
+		return true
+	case s.domAny:
+		return dow
+	case s.dowAny:
+		return dom
+	default:
+		return dom || dow
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSchedule(t *testing.T) {
+	// Wednesday
+	after := time.Date(2025, 1, 15, 10, 7, 30, 0, time.UTC)
+
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"*/15 * * * *", time.Date(2025, 1, 15, 10, 15, 0, 0, time.UTC)},
+		{"0 * * * *", time.Date(2025, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2025, 1, 15, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2025, 1, 19, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"30 9 * * 1-5", time.Date(2025, 1, 16, 9, 30, 0, 0, time.UTC)},
+		{"0 8 * * 7", time.Date(2025, 1, 19, 8, 0, 0, 0, time.UTC)},
+		{"0 0 1,20 * *", time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)},
+		{"5-10/5 10 * * *", time.Date(2025, 1, 15, 10, 10, 0, 0, time.UTC)},
+		// Restricted day of month and day of week match either
+		{"0 12 31 * 4", time.Date(2025, 1, 16, 12, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{"@every 30m", time.Date(2025, 1, 15, 10, 37, 30, 0, time.UTC)},
+		{"  @every 2h ", time.Date(2025, 1, 15, 12, 7, 30, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		sched, err := parseSchedule(tt.spec)
+		if err != nil {
+			t.Errorf("parseSchedule(%q): %v", tt.spec, err)
+			continue
+		}
+		got := sched.next(after)
+		// Feb 29 is more than a year away, past what next scans
+		if tt.spec == "0 0 29 2 *" {
+			if !got.IsZero() {
+				t.Errorf("parseSchedule(%q).next = %v, want none within a year", tt.spec, got)
+			}
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseSchedule(%q).next = %v, want %v", tt.spec, got, tt.want)
+		}
+	}
+}
+
+func TestParseScheduleInvalid(t *testing.T) {
+	for _, spec := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"@yearly",
+		"@every 30s",
+		"@every soon",
+	} {
+		if _, err := parseSchedule(spec); err == nil {
+			t.Errorf("parseSchedule(%q) succeeded, want an error", spec)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package main
+
+// ScoringSettings weighs matches so strong articles can be told from passing
+// mentions. Weights are keyed by keyword, category or any other term (plain or
+// /regex/); matched keywords without a weight count 1, and weighted terms that
+// are not keywords only add to the score, e.g. "bounty". Articles scoring
+// below Threshold are not notified.
+type ScoringSettings struct {
+	Weights   map[string]float64 `yaml:"weights"`
+	Threshold float64            `yaml:"threshold"`
+}
+
+// scoringEnabled reports whether scores are computed and shown
+func scoringEnabled() bool {
+	return len(settings.Scoring.Weights) > 0 || settings.Scoring.Threshold > 0
+}
+
+// keywordWeight is the weight of a matched keyword: its own, its tag's, or 1
+func keywordWeight(term, tag string) float64 {
+	if weight, exists := settings.Scoring.Weights[term]; exists {
+		return weight
+	}
+	if weight, exists := settings.Scoring.Weights[tag]; exists {
+		return weight
+	}
+	return 1
+}
+
+// boosterScore adds the weights of weighted terms that are not routing
+// keywords or tags themselves
+func boosterScore(text, lowerText string, keywords map[string]string, tags map[string]struct{}) float64 {
+	var score float64
+	for term, weight := range settings.Scoring.Weights {
+		if _, isKeyword := keywords[term]; isKeyword {
+			continue
+		}
+		if _, isTag := tags[term]; isTag {
+			continue
+		}
+		if matchesKeyword(text, lowerText, term) {
+			score += weight
+		}
+	}
+	return score
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestProcessArticleScoring(t *testing.T) {
+	categories := map[string]Category{
+		"xss": {Thread: "3", Terms: []string{"xss", "cross site scripting"}},
+	}
+	tests := []struct {
+		name      string
+		weights   map[string]float64
+		threshold float64
+		text      string
+		wantTags  string
+		wantScore float64
+	}{
+		{"unweighted terms count 1", nil, 0, "XSS and IDOR in one app", "idor xss", 0},
+		{"synonyms take the highest weight", map[string]float64{"xss": 2, "cross site scripting": 5}, 0,
+			"Cross site scripting (XSS) in a search box", "xss", 5},
+		{"category weight applies to its terms", map[string]float64{"xss": 4}, 0, "Stored cross site scripting", "xss", 4},
+		{"boosters add to matches", map[string]float64{"bounty": 2}, 0, "XSS worth a bounty", "xss", 3},
+		{"below threshold", map[string]float64{"idor": 1}, 2, "An IDOR writeup", "", 0},
+		{"at threshold", map[string]float64{"idor": 2}, 2, "An IDOR writeup", "idor", 2},
+	}
+	for _, tt := range tests {
+		useSettings(t, Settings{
+			Categories: categories,
+			Scoring:    ScoringSettings{Weights: tt.weights, Threshold: tt.threshold},
+		})
+		keywords := applyCategories(map[string]string{"idor": "5"})
+
+		// Map order must not change the outcome
+		for range 20 {
+			article := processArticle(&gofeed.Item{Title: tt.text, Link: "https://example.com/a"}, nil, keywords)
+			if tt.wantTags == "" {
+				if article != nil {
+					t.Fatalf("%s: matched %v, want no match", tt.name, article.Keywords)
+				}
+				continue
+			}
+			if article == nil {
+				t.Fatalf("%s: no match", tt.name)
+			}
+			if got := strings.Join(article.Keywords, " "); got != tt.wantTags {
+				t.Fatalf("%s: tags %q, want %q", tt.name, got, tt.wantTags)
+			}
+			if scoringEnabled() && article.Score != tt.wantScore {
+				t.Fatalf("%s: score %g, want %g", tt.name, article.Score, tt.wantScore)
+			}
+		}
+	}
+}
+
+func TestKeywordWeight(t *testing.T) {
+	useSettings(t, Settings{Scoring: ScoringSettings{Weights: map[string]float64{"rce": 3, "injection": 2}}})
+
+	tests := []struct {
+		term, tag string
+		want      float64
+	}{
+		{"rce", "rce", 3},
+		{"command injection", "injection", 2},
+		{"rce", "injection", 3},
+		{"idor", "idor", 1},
+	}
+	for _, tt := range tests {
+		if got := keywordWeight(tt.term, tt.tag); got != tt.want {
+			t.Errorf("keywordWeight(%q, %q) = %g, want %g", tt.term, tt.tag, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// interpolationPattern matches ${VAR} references in hunter.yaml; a bare $ is
+// left alone so selectors and patterns can use it literally
+var interpolationPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// lookupSecret reads a variable from the environment, or from the file named by
+// its _FILE variant (Docker and Kubernetes secret mounts)
+func lookupSecret(key string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	path := os.Getenv(key + "_FILE")
+	if path == "" {
+		return ""
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		printError("reading " + key + "_FILE: " + err.Error())
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// interpolateEnv replaces ${VAR} with its value; unset variables become empty
+func interpolateEnv(text string) string {
+	return interpolationPattern.ReplaceAllStringFunc(text, func(ref string) string {
+		return getEnvForEnvironment(interpolationPattern.FindStringSubmatch(ref)[1])
+	})
+}
+
+// interpolateNode resolves ${VAR} in the string values of a parsed document.
+// Keys, numbers and booleans are left alone, and a value containing quotes or
+// newlines cannot change the document's structure.
+func interpolateNode(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			interpolateNode(child)
+		}
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			interpolateNode(node.Content[i])
+		}
+	case yaml.ScalarNode:
+		if node.Tag == "!!str" {
+			node.Value = interpolateEnv(node.Value)
+		}
+	}
+}
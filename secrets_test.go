@@ -0,0 +1,87 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInterpolateEnv(t *testing.T) {
+	t.Setenv("HUNTER_TEST_TOKEN", "s3cret")
+	t.Setenv("HUNTER_TEST_EMPTY", "")
+
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"${HUNTER_TEST_TOKEN}", "s3cret"},
+		{"Bearer ${HUNTER_TEST_TOKEN}!", "Bearer s3cret!"},
+		{"${HUNTER_TEST_TOKEN}${HUNTER_TEST_TOKEN}", "s3crets3cret"},
+		{"${HUNTER_TEST_UNSET}", ""},
+		{"${HUNTER_TEST_EMPTY}", ""},
+		{"$HUNTER_TEST_TOKEN", "$HUNTER_TEST_TOKEN"},
+		{"price: $5", "price: $5"},
+		{"${1INVALID}", "${1INVALID}"},
+	}
+	for _, tt := range tests {
+		if got := interpolateEnv(tt.input); got != tt.want {
+			t.Errorf("interpolateEnv(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestInterpolateEnvFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(path, []byte("from-file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HUNTER_TEST_FILE_TOKEN_FILE", path)
+
+	if got := interpolateEnv("${HUNTER_TEST_FILE_TOKEN}"); got != "from-file" {
+		t.Errorf("got %q, want %q", got, "from-file")
+	}
+}
+
+func TestLoadSettingsInterpolatesStringValues(t *testing.T) {
+	// A value with YAML syntax must stay a plain string
+	t.Setenv("HUNTER_TEST_LISTEN", "\"127.0.0.1:9090\"\nproxy: http://evil")
+	t.Setenv("HUNTER_TEST_KEY", "sk-test")
+
+	path := filepath.Join(t.TempDir(), "hunter.yaml")
+	data := `listen: ${HUNTER_TEST_LISTEN}
+classifier:
+  provider: openai
+  api_key: "${HUNTER_TEST_KEY}"
+  model: gpt-${HUNTER_TEST_UNSET}mini
+`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := loadSettings(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Listen != "\"127.0.0.1:9090\"\nproxy: http://evil" {
+		t.Errorf("listen = %q", s.Listen)
+	}
+	if s.Proxy != "" {
+		t.Errorf("proxy = %q, want it unset", s.Proxy)
+	}
+	if s.Classifier.APIKey != "sk-test" {
+		t.Errorf("api_key = %q, want sk-test", s.Classifier.APIKey)
+	}
+	if s.Classifier.Model != "gpt-mini" {
+		t.Errorf("model = %q, want gpt-mini", s.Classifier.Model)
+	}
+}
+
+func TestLoadSettingsEmpty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hunter.yaml")
+	if err := os.WriteFile(path, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadSettings(path); err != nil {
+		t.Errorf("empty settings: %v", err)
+	}
+}
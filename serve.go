@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// defaultSchedule applies when neither the profile nor hunter.yaml sets one
+const defaultSchedule = "@hourly"
+
+// scheduleFor resolves the schedule of a profile in daemon mode
+func scheduleFor(p Profile) (schedule, error) {
+	spec := firstNonEmpty(p.Schedule, settings.Schedule, defaultSchedule)
+	sched, err := parseSchedule(spec)
+	if err != nil && p.Name != "" {
+		return nil, fmt.Errorf("profile %s: %w", p.Name, err)
+	}
+	return sched, err
+}
+
+// schedulesFor resolves the schedule of every profile
+func schedulesFor(profiles []Profile) ([]schedule, error) {
+	schedules := make([]schedule, len(profiles))
+	for i, profile := range profiles {
+		sched, err := scheduleFor(profile)
+		if err != nil {
+			return nil, err
+		}
+		schedules[i] = sched
+	}
+	return schedules, nil
+}
+
+// reloadServe re-reads hunter.yaml with its keyword categories and resolves
+// the profiles and schedules again. Nothing changes unless all of it is valid.
+// Proxy, DNS and HTTP client settings keep their values until a restart.
+func reloadServe(channelID string) ([]Profile, []schedule, RunConfig, error) {
+	previous := struct {
+		settings        Settings
+		categoryOfTerm  map[string]string
+		categoryThreads map[string]string
+		disabledTerms   map[string]struct{}
+	}{settings, categoryOfTerm, categoryThreads, disabledTerms}
+
+	if err := loadConfiguration(); err != nil {
+		return nil, nil, RunConfig{}, err
+	}
+	profiles, err := runnableProfiles(channelID)
+	var schedules []schedule
+	if err == nil {
+		schedules, err = schedulesFor(profiles)
+	}
+	if err != nil {
+		settings = previous.settings
+		categoryOfTerm, categoryThreads, disabledTerms = previous.categoryOfTerm, previous.categoryThreads, previous.disabledTerms
+		return nil, nil, RunConfig{}, err
+	}
+	return profiles, schedules, runConfigFromSettings(), nil
+}
+
+// runServe keeps the process alive and runs each profile when its schedule
+// is due, until SIGINT or SIGTERM. Profiles due at the same minute share a run.
+// SIGHUP between runs reloads hunter.yaml, keywords and schedules.
+func runServe(profiles []Profile, botToken, channelID string, config RunConfig) error {
+	schedules, err := schedulesFor(profiles)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if settings.Listen != "" {
+		server := newDaemonServer()
+		go func() {
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				printError(fmt.Sprintf("HTTP server on %s: %v", settings.Listen, err))
+			}
+		}()
+		defer server.Close()
+		printStatus(fmt.Sprintf("Serving /metrics, /healthz and /readyz on %s", settings.Listen), color.FgCyan)
+	}
+
+	if settings.Pprof.Enabled {
+		defer startPprof()()
+	}
+
+	// Runs re-read the feed list themselves; SIGHUP reloads the rest
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	nextRuns := make([]time.Time, len(profiles))
+	now := time.Now()
+	for i, sched := range schedules {
+		nextRuns[i] = sched.next(now)
+	}
+	setHealthStaleness(staleAfter(schedules, config.RunTimeout, now))
+
+scheduling:
+	for {
+		earliest := time.Time{}
+		for _, next := range nextRuns {
+			if !next.IsZero() && (earliest.IsZero() || next.Before(earliest)) {
+				earliest = next
+			}
+		}
+		if earliest.IsZero() {
+			return fmt.Errorf("no schedule has a future run")
+		}
+		printStatus(fmt.Sprintf("Next run at %s", earliest.Format("2006-01-02 15:04")), color.FgCyan)
+
+		timer := time.NewTimer(time.Until(earliest))
+	wait:
+		for {
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				printStatus("Stopping", color.FgYellow)
+				return nil
+			case <-hangup:
+				reloaded, reloadedSchedules, reloadedConfig, err := reloadServe(channelID)
+				if err != nil {
+					printError(fmt.Sprintf("Reload failed, keeping the current configuration: %v", err))
+					continue
+				}
+				profiles, schedules, config = reloaded, reloadedSchedules, reloadedConfig
+				nextRuns = make([]time.Time, len(profiles))
+				for i, sched := range schedules {
+					nextRuns[i] = sched.next(time.Now())
+				}
+				setHealthStaleness(staleAfter(schedules, config.RunTimeout, time.Now()))
+				timer.Stop()
+				printStatus(fmt.Sprintf("Reloaded %s: %d profile(s)", settingsFileName, len(profiles)), color.FgYellow)
+				continue scheduling
+			case <-timer.C:
+				break wait
+			}
+		}
+
+		var due []Profile
+		for i, next := range nextRuns {
+			if !next.After(time.Now()) {
+				due = append(due, profiles[i])
+			}
+		}
+		runOnce(ctx, due, botToken, config)
+
+		now = time.Now()
+		for i, next := range nextRuns {
+			if !next.After(now) {
+				nextRuns[i] = schedules[i].next(now)
+			}
+		}
+	}
+}
+
+// newDaemonServer builds the daemon's HTTP server
+func newDaemonServer() *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", handleMetrics)
+	mux.HandleFunc("/healthz", handleHealthz)
+	mux.HandleFunc("/readyz", handleReadyz)
+	return &http.Server{Addr: settings.Listen, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestReloadServe(t *testing.T) {
+	t.Chdir(t.TempDir())
+	saved := settings
+	t.Cleanup(func() { settings = saved })
+	settings = Settings{Schedule: "@daily"}
+
+	write := func(data string) {
+		t.Helper()
+		if err := os.WriteFile(settingsFileName, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("schedule: \"*/15 * * * *\"\nfeed_timeout: 45s\n")
+	profiles, schedules, config, err := reloadServe("-100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(profiles) != 1 || len(schedules) != 1 {
+		t.Fatalf("got %d profiles and %d schedules, want 1 each", len(profiles), len(schedules))
+	}
+	if settings.Schedule != "*/15 * * * *" || config.FeedTimeout.String() != "45s" {
+		t.Errorf("reload not applied: schedule %q, feed timeout %s", settings.Schedule, config.FeedTimeout)
+	}
+
+	// An invalid schedule keeps everything as it was
+	write("schedule: \"every tuesday\"\nfeed_timeout: 10s\n")
+	if _, _, _, err := reloadServe("-100"); err == nil {
+		t.Fatal("reload with an invalid schedule succeeded")
+	}
+	if settings.Schedule != "*/15 * * * *" || settings.FeedTimeout.String() != "45s" {
+		t.Errorf("failed reload changed settings: schedule %q, feed timeout %s", settings.Schedule, settings.FeedTimeout)
+	}
+
+	write("categories: [not, a, map]\n")
+	if _, _, _, err := reloadServe("-100"); err == nil {
+		t.Fatal("reload of malformed settings succeeded")
+	}
+	if settings.Schedule != "*/15 * * * *" {
+		t.Errorf("failed reload changed settings: schedule %q", settings.Schedule)
+	}
+}
+
+func TestServeHealthDuringRun(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile(urlsFileName, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	// Quiet hours with hold keep every message in the outbox, so the run
+	// fills and saves it without reaching Telegram
+	now := time.Now()
+	useSettings(t, Settings{QuietHours: QuietHoursSettings{
+		Start: now.Add(-time.Hour).Format(quietTimeLayout),
+		End:   now.Add(time.Hour).Format(quietTimeLayout),
+		Hold:  true,
+	}})
+	savedQuiet := quiet
+	quiet = true
+	t.Cleanup(func() { quiet = savedQuiet })
+
+	profiles, err := selectProfiles(nil, "", "-100")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Poll the handlers as serve's HTTP server would while runs go on
+	done := make(chan struct{})
+	polled := make(chan struct{})
+	go func() {
+		defer close(polled)
+		for {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			for _, handler := range []http.HandlerFunc{handleHealthz, handleMetrics} {
+				handler(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+			}
+		}
+	}()
+
+	for range 3 {
+		runOnce(context.Background(), profiles, "token", defaultRunConfig)
+		// Let the handlers read the outbox between runs
+		time.Sleep(10 * time.Millisecond)
+	}
+	close(done)
+	<-polled
+
+	if outbox.pending() == 0 {
+		t.Error("the run did not hold its report in the outbox")
+	}
+	messages, err := readOutbox(stateFileName(outboxFileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(messages) != outbox.pending() {
+		t.Errorf("saved %d messages, want %d", len(messages), outbox.pending())
+	}
+	outbox.drain()
+}
@@ -0,0 +1,72 @@
+package main
+
+import "strings"
+
+// Severity levels, lowest first
+var severityLevels = []string{"info", "medium", "high", "critical"}
+
+// defaultSeverityTerms label articles when hunter.yaml sets no terms of its
+// own; terms may be plain or /regex/ and are matched against the article text
+// and its matched categories
+var defaultSeverityTerms = map[string][]string{
+	"critical": {"remote code execution", `/\bRCE\b/`, "pre-auth", "unauthenticated", "account takeover", "command injection"},
+	"high":     {"sql injection", "sqli", "ssrf", "authentication bypass", "auth bypass", "privilege escalation", "idor", "deserialization"},
+	"medium":   {"xss", "cross-site scripting", "csrf", "open redirect", "information disclosure"},
+}
+
+// SeveritySettings tunes the heuristic severity label. Terms replace the
+// defaults per level, Min drops articles below a level, and Threads posts a
+// copy of articles at a level to a thread.
+type SeveritySettings struct {
+	Terms   map[string][]string `yaml:"terms"`
+	Min     string              `yaml:"min"`
+	Threads map[string]string   `yaml:"threads"`
+}
+
+// severityRank orders levels; unknown levels rank below info
+func severityRank(level string) int {
+	for i, known := range severityLevels {
+		if known == level {
+			return i
+		}
+	}
+	return -1
+}
+
+// severityTerms returns the configured terms for a level, or the defaults
+func severityTerms(level string) []string {
+	if terms, exists := settings.Severity.Terms[level]; exists {
+		return terms
+	}
+	return defaultSeverityTerms[level]
+}
+
+// articleSeverity picks the highest level whose terms appear in the text or
+// among the matched tags
+func articleSeverity(text string, tags []string) string {
+	lowerText := strings.ToLower(text)
+	for i := len(severityLevels) - 1; i > 0; i-- {
+		level := severityLevels[i]
+		for _, term := range severityTerms(level) {
+			if matchesKeyword(text, lowerText, term) {
+				return level
+			}
+			for _, tag := range tags {
+				if strings.EqualFold(tag, term) {
+					return level
+				}
+			}
+		}
+	}
+	return severityLevels[0]
+}
+
+// severityAllowed applies the minimum severity filter
+func severityAllowed(level string) bool {
+	return settings.Severity.Min == "" || severityRank(level) >= severityRank(settings.Severity.Min)
+}
+
+// severityThread returns the thread configured for a severity level, if any
+func severityThread(level string) string {
+	return settings.Severity.Threads[level]
+}
@@ -0,0 +1,59 @@
+package main
+
+import "testing"
+
+func TestArticleSeverity(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings SeveritySettings
+		text     string
+		tags     []string
+		want     string
+	}{
+		{name: "nothing known", text: "A blog post about tooling", want: "info"},
+		{name: "medium term", text: "Reflected XSS in search", want: "medium"},
+		{name: "highest level wins", text: "SSRF leading to remote code execution", want: "critical"},
+		{name: "regex term", text: "Chaining bugs into RCE", want: "critical"},
+		{name: "regex needs a word", text: "A source review", want: "info"},
+		{name: "matched tag", text: "A writeup", tags: []string{"IDOR"}, want: "high"},
+		{
+			name:     "configured terms replace the defaults",
+			settings: SeveritySettings{Terms: map[string][]string{"medium": {"clickjacking"}}},
+			text:     "Reflected XSS and clickjacking",
+			want:     "medium",
+		},
+		{
+			name:     "configured level without the default terms",
+			settings: SeveritySettings{Terms: map[string][]string{"medium": {"clickjacking"}}},
+			text:     "Reflected XSS",
+			want:     "info",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useSettings(t, Settings{Severity: tt.settings})
+			if got := articleSeverity(tt.text, tt.tags); got != tt.want {
+				t.Errorf("articleSeverity(%q, %v) = %q, want %q", tt.text, tt.tags, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeverityAllowed(t *testing.T) {
+	tests := []struct {
+		min, level string
+		want       bool
+	}{
+		{"", "info", true},
+		{"high", "medium", false},
+		{"high", "high", true},
+		{"high", "critical", true},
+		{"medium", "unknown", false},
+	}
+	for _, tt := range tests {
+		useSettings(t, Settings{Severity: SeveritySettings{Min: tt.min}})
+		if got := severityAllowed(tt.level); got != tt.want {
+			t.Errorf("severityAllowed(%q) with min %q = %v, want %v", tt.level, tt.min, got, tt.want)
+		}
+	}
+}
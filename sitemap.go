@@ -0,0 +1,153 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mmcdole/gofeed"
+)
+
+const (
+	feedTypeSitemap     = "sitemap"
+	defaultSitemapLimit = 20
+	maxNestedSitemaps   = 5
+)
+
+// sitemapDocument covers both <urlset> sitemaps and <sitemapindex> indexes
+type sitemapDocument struct {
+	XMLName  xml.Name
+	URLs     []sitemapEntry `xml:"url"`
+	Sitemaps []sitemapEntry `xml:"sitemap"`
+}
+
+type sitemapEntry struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+// fetchSitemap turns the most recently modified sitemap URLs into items that
+// only carry a link and date; fillFromPage completes the ones not seen yet.
+// Sitemap indexes are followed one level deep, newest child sitemaps first.
+func fetchSitemap(ctx context.Context, sitemapURL string) ([]*gofeed.Item, error) {
+	doc, err := fetchSitemapDocument(ctx, sitemapURL)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := doc.URLs
+	if len(doc.Sitemaps) > 0 {
+		children := doc.Sitemaps
+		sortSitemapEntries(children)
+		if len(children) > maxNestedSitemaps {
+			children = children[:maxNestedSitemaps]
+		}
+		for _, child := range children {
+			childDoc, err := fetchSitemapDocument(ctx, strings.TrimSpace(child.Loc))
+			if err != nil {
+				printError(fmt.Sprintf("Error fetching sitemap %s: %v", child.Loc, err))
+				continue
+			}
+			entries = append(entries, childDoc.URLs...)
+		}
+	}
+
+	sortSitemapEntries(entries)
+	limit := feedOptions(sitemapURL).Limit
+	if limit <= 0 {
+		limit = defaultSitemapLimit
+	}
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	items := make([]*gofeed.Item, 0, len(entries))
+	for _, entry := range entries {
+		items = append(items, &gofeed.Item{
+			Link:      strings.TrimSpace(entry.Loc),
+			Published: strings.TrimSpace(entry.LastMod),
+		})
+	}
+	return items, nil
+}
+
+func fetchSitemapDocument(ctx context.Context, sitemapURL string) (*sitemapDocument, error) {
+	body, err := fetchFeedBody(ctx, sitemapURL)
+	if err != nil {
+		return nil, err
+	}
+	var doc sitemapDocument
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("parsing sitemap: %w", err)
+	}
+	return &doc, nil
+}
+
+// sortSitemapEntries orders entries newest first; lastmod is ISO 8601 so the
+// string comparison matches chronological order for a single site
+func sortSitemapEntries(entries []sitemapEntry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].LastMod > entries[j].LastMod
+	})
+}
+
+// fillFromPage fetches an article page to fill in the title, description and
+// date that sitemap entries lack
+func fillFromPage(ctx context.Context, item *gofeed.Item) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, item.Link, nil)
+	if err != nil {
+		return fmt.Errorf("creating request: %w", err)
+	}
+	setUserAgent(req)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("fetching page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return &HTTPError{StatusCode: resp.StatusCode}
+	}
+
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		return fmt.Errorf("reading page: %w", err)
+	}
+	doc, err := goquery.NewDocumentFromReader(&buf)
+	if err != nil {
+		return fmt.Errorf("parsing page: %w", err)
+	}
+
+	item.Title = firstNonEmpty(
+		doc.Find(`meta[property="og:title"]`).AttrOr("content", ""),
+		strings.TrimSpace(doc.Find("title").First().Text()),
+		item.Link,
+	)
+	item.Description = firstNonEmpty(
+		doc.Find(`meta[property="og:description"]`).AttrOr("content", ""),
+		doc.Find(`meta[name="description"]`).AttrOr("content", ""),
+	)
+	item.Published = firstNonEmpty(
+		doc.Find(`meta[property="article:published_time"]`).AttrOr("content", ""),
+		item.Published,
+		// Without any date the entry is new to us, which is what matters here
+		time.Now().UTC().Format(time.RFC3339),
+	)
+	return nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			return value
+		}
+	}
+	return ""
+}
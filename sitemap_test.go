@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchSitemap(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/index.xml":
+			fmt.Fprintf(w, `<?xml version="1.0"?>
+<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>%[1]s/old.xml</loc><lastmod>2023-01-01</lastmod></sitemap>
+  <sitemap><loc>%[1]s/posts.xml</loc><lastmod>2024-06-01</lastmod></sitemap>
+</sitemapindex>`, server.URL)
+		case "/posts.xml":
+			fmt.Fprint(w, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc> https://blog.example.com/a </loc><lastmod>2024-05-01</lastmod></url>
+  <url><loc>https://blog.example.com/b</loc><lastmod>2024-06-01T10:00:00Z</lastmod></url>
+</urlset>`)
+		case "/old.xml":
+			fmt.Fprint(w, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>https://blog.example.com/old</loc><lastmod>2023-01-01</lastmod></url>
+</urlset>`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	items, err := fetchSitemap(context.Background(), server.URL+"/index.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct{ link, published string }{
+		{"https://blog.example.com/b", "2024-06-01T10:00:00Z"},
+		{"https://blog.example.com/a", "2024-05-01"},
+		{"https://blog.example.com/old", "2023-01-01"},
+	}
+	if len(items) != len(want) {
+		t.Fatalf("got %d items, want %d", len(items), len(want))
+	}
+	for i, w := range want {
+		if items[i].Link != w.link || items[i].Published != w.published {
+			t.Errorf("item %d = %s (%s), want %s (%s)", i, items[i].Link, items[i].Published, w.link, w.published)
+		}
+		if _, err := parseDate(items[i].Published); err != nil {
+			t.Errorf("item %d date: %v", i, err)
+		}
+	}
+}
+
+func TestSortSitemapEntries(t *testing.T) {
+	tests := []struct {
+		name    string
+		lastMod []string
+		want    []string
+	}{
+		{"dates", []string{"2024-01-02", "2024-03-01", "2023-12-31"}, []string{"2024-03-01", "2024-01-02", "2023-12-31"}},
+		{"undated last", []string{"", "2024-01-02"}, []string{"2024-01-02", ""}},
+	}
+	for _, tt := range tests {
+		entries := make([]sitemapEntry, len(tt.lastMod))
+		for i, lastMod := range tt.lastMod {
+			entries[i].LastMod = lastMod
+		}
+		sortSitemapEntries(entries)
+		for i, entry := range entries {
+			if entry.LastMod != tt.want[i] {
+				t.Errorf("%s: entry %d = %q, want %q", tt.name, i, entry.LastMod, tt.want[i])
+			}
+		}
+	}
+}
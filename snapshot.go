@@ -0,0 +1,131 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/chromedp/cdproto/page"
+	"github.com/chromedp/chromedp"
+)
+
+const (
+	snapshotFormatHTML = "html"
+	snapshotFormatPDF  = "pdf"
+	snapshotTimeout    = 90 * time.Second
+	maxSnapshotSlug    = 80
+)
+
+// SnapshotSettings saves a copy of every matched article under
+// Dir/<date>/<first tag>/, as the raw HTML page or a PDF rendered by headless
+// Chrome
+type SnapshotSettings struct {
+	Dir    string `yaml:"dir"`
+	Format string `yaml:"format"`
+}
+
+var snapshotSlugPattern = regexp.MustCompile(`[^a-z0-9]+`)
+
+// snapshotPath names an article's snapshot file
+func snapshotPath(article *Article, now time.Time, format string) string {
+	tag := "untagged"
+	if len(article.Keywords) > 0 {
+		tag = snapshotSlugPattern.ReplaceAllString(strings.ToLower(article.Keywords[0]), "-")
+	}
+
+	slug := strings.Trim(snapshotSlugPattern.ReplaceAllString(strings.ToLower(article.Title), "-"), "-")
+	if len(slug) > maxSnapshotSlug {
+		slug = strings.TrimRight(slug[:maxSnapshotSlug], "-")
+	}
+	sum := sha256.Sum256([]byte(article.Link))
+	name := hex.EncodeToString(sum[:4])
+	if slug != "" {
+		name = slug + "-" + name
+	}
+	return filepath.Join(settings.Snapshot.Dir, now.Format("2006-01-02"), tag, name+"."+format)
+}
+
+// saveSnapshot stores a matched article's page in the snapshot archive
+func saveSnapshot(ctx context.Context, article *Article) (string, error) {
+	format := firstNonEmpty(settings.Snapshot.Format, snapshotFormatHTML)
+
+	ctx, cancel := context.WithTimeout(ctx, snapshotTimeout)
+	defer cancel()
+
+	var data []byte
+	var err error
+	switch format {
+	case snapshotFormatHTML:
+		data, err = fetchPageHTML(ctx, article.Link)
+	case snapshotFormatPDF:
+		data, err = printPDF(ctx, article.Link)
+	default:
+		return "", fmt.Errorf("unknown snapshot format %q", format)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	path := snapshotPath(article, time.Now(), format)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", fmt.Errorf("creating %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("writing %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// fetchPageHTML downloads a page as served
+func fetchPageHTML(ctx context.Context, link string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	setUserAgent(req)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &HTTPError{StatusCode: resp.StatusCode}
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading page: %w", err)
+	}
+	return body, nil
+}
+
+// printPDF renders a page in headless Chrome and prints it to PDF
+func printPDF(ctx context.Context, link string) ([]byte, error) {
+	browserCtx, cancel := newBrowser(ctx, link)
+	defer cancel()
+
+	var pdf []byte
+	err := chromedp.Run(browserCtx,
+		chromedp.Navigate(link),
+		chromedp.WaitReady("body"),
+		chromedp.Sleep(defaultRenderWait),
+		chromedp.ActionFunc(func(ctx context.Context) error {
+			var err error
+			pdf, _, err = page.PrintToPDF().WithPrintBackground(true).Do(ctx)
+			return err
+		}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("printing %s to PDF: %w", link, err)
+	}
+	return pdf, nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"time"
+)
+
+// percentile returns the p-th percentile (0-100) of sorted values using the
+// nearest-rank method
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// LatencyStats summarizes publish-to-delivery latency for one source
+type LatencyStats struct {
+	Source string
+	Count  int
+	P50    time.Duration
+	P95    time.Duration
+}
+
+// latencyBySource groups delivered records by source domain
+func latencyBySource(records []StoredArticle) []LatencyStats {
+	bySource := make(map[string][]time.Duration)
+	for _, record := range records {
+		if !record.Notified || record.LatencySeconds <= 0 {
+			continue
+		}
+		source := getDomain(record.Source)
+		bySource[source] = append(bySource[source], time.Duration(record.LatencySeconds*float64(time.Second)))
+	}
+
+	stats := make([]LatencyStats, 0, len(bySource))
+	for source, latencies := range bySource {
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		stats = append(stats, LatencyStats{
+			Source: source,
+			Count:  len(latencies),
+			P50:    percentile(latencies, 50),
+			P95:    percentile(latencies, 95),
+		})
+	}
+	// Slowest sources first, since those are the ones worth polling more often
+	sort.Slice(stats, func(i, j int) bool { return stats[i].P50 > stats[j].P50 })
+	return stats
+}
+
+// formatLatency rounds to a readable unit
+func formatLatency(d time.Duration) string {
+	if d >= 48*time.Hour {
+		return fmt.Sprintf("%.1fd", d.Hours()/24)
+	}
+	return d.Round(time.Minute).String()
+}
+
+// runStats prints delivery latency per source from the article store of each
+// profile selected with --profile
+func runStats(args []string) error {
+	profiles, err := commandProfiles()
+	if err != nil {
+		return err
+	}
+	for _, profile := range profiles {
+		records, err := readStoredArticles(profile.stateFile(articleStoreFileName))
+		if err != nil {
+			return err
+		}
+		if err := printLatencyStats(profile, latencyBySource(records)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func printLatencyStats(profile Profile, stats []LatencyStats) error {
+	if len(stats) == 0 {
+		fmt.Println(profile.label() + "No delivered articles with a publish date yet")
+		return nil
+	}
+
+	if profile.Name != "" {
+		fmt.Printf("[%s]\n", profile.Name)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "SOURCE\tDELIVERED\tP50 LATENCY\tP95 LATENCY")
+	for _, s := range stats {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", s.Source, s.Count, formatLatency(s.P50), formatLatency(s.P95))
+	}
+	return w.Flush()
+}
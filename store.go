@@ -0,0 +1,187 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// articleStoreFileName keeps a JSON line per known article for search,
+// related-article lookups and archiving
+const articleStoreFileName = "articles.jsonl"
+
+// StoredArticle is one record in the article store
+type StoredArticle struct {
+	Link       string        `json:"link"`
+	Title      string        `json:"title"`
+	Published  string        `json:"published,omitempty"`
+	Author     string        `json:"author,omitempty"`
+	Tags       []string      `json:"tags,omitempty"`
+	CVEs       []string      `json:"cves,omitempty"`
+	CVEDetails []CVEDetail   `json:"cve_details,omitempty"`
+	Archive    string        `json:"archive,omitempty"`
+	Summary    string        `json:"summary,omitempty"`
+	WordCount  int           `json:"word_count,omitempty"`
+	Language   string        `json:"language,omitempty"`
+	Severity   string        `json:"severity,omitempty"`
+	Content    string        `json:"content,omitempty"`
+	Source     string        `json:"source,omitempty"`
+	Notified   bool          `json:"notified"`
+	Messages   []SentMessage `json:"messages,omitempty"`
+	IngestedAt time.Time     `json:"ingested_at"`
+
+	// LatencySeconds is the time from publication to notification
+	LatencySeconds float64 `json:"latency_seconds,omitempty"`
+}
+
+// newStoredArticle builds a store record from a processed article
+func newStoredArticle(article *Article, source string, notified bool) StoredArticle {
+	record := StoredArticle{
+		Link:       article.Link,
+		Title:      article.Title,
+		Published:  article.Published,
+		Author:     article.Author,
+		Tags:       append(append(append([]string(nil), article.Keywords...), article.CVEs...), article.Programs...),
+		CVEs:       article.CVEs,
+		CVEDetails: article.CVEDetails,
+		Archive:    article.ArchiveURL,
+		Summary:    article.Summary,
+		WordCount:  article.WordCount,
+		Language:   article.Language,
+		Severity:   article.Severity,
+		Content:    article.Content,
+		Source:     source,
+		Notified:   notified,
+		Messages:   article.Messages,
+		IngestedAt: time.Now().UTC(),
+	}
+	if article.Video {
+		record.Tags = append(record.Tags, videoCategoryTag)
+	}
+	if notified {
+		record.delivered(record.IngestedAt)
+	}
+	return record
+}
+
+// delivered marks the record notified at the given time, with its latency
+// when the publish date is known
+func (record *StoredArticle) delivered(at time.Time) {
+	record.Notified = true
+	if published, err := parseDate(record.Published); err == nil {
+		record.LatencySeconds = at.Sub(published).Seconds()
+	}
+}
+
+// markStoredDelivered marks articles stored before any of their messages went
+// out (queued, held for quiet hours or batched into a digest) as notified now,
+// and returns the records it updated
+func markStoredDelivered(filename string, links []string, at time.Time) ([]StoredArticle, error) {
+	pending := make(map[string]struct{}, len(links))
+	for _, link := range links {
+		pending[link] = struct{}{}
+	}
+
+	records, err := readStoredArticles(filename)
+	if err != nil {
+		return nil, err
+	}
+	var updated []StoredArticle
+	for i := range records {
+		if _, exists := pending[records[i].Link]; !exists || records[i].Notified {
+			continue
+		}
+		records[i].delivered(at.UTC())
+		updated = append(updated, records[i])
+	}
+	if len(updated) == 0 {
+		return nil, nil
+	}
+	return updated, writeStoredArticles(filename, records)
+}
+
+// recordDelivered stores and exports the latency of articles whose delivery
+// was deferred past ingestion
+func recordDelivered(filename string, links ...string) {
+	updated, err := markStoredDelivered(filename, links, time.Now())
+	if err != nil {
+		printError(fmt.Sprintf("Error recording delivery: %v", err))
+		return
+	}
+	for _, record := range updated {
+		if record.LatencySeconds > 0 {
+			metricDeliveryLatency(record.Source, record.LatencySeconds)
+		}
+	}
+}
+
+// appendStoredArticles adds records to the store file
+func appendStoredArticles(filename string, records ...StoredArticle) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	file, err := os.OpenFile(filename, os.O_APPEND|os.O_WRONLY|os.O_CREATE, 0644)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return fmt.Errorf("writing to %s: %w", filename, err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("writing to %s: %w", filename, err)
+	}
+	return nil
+}
+
+// writeStoredArticles replaces the store file with records
+func writeStoredArticles(filename string, records []StoredArticle) error {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return fmt.Errorf("encoding %s: %w", filename, err)
+		}
+	}
+	if err := os.WriteFile(filename, buf.Bytes(), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", filename, err)
+	}
+	return nil
+}
+
+// readStoredArticles loads every record; a missing store is empty
+func readStoredArticles(filename string) ([]StoredArticle, error) {
+	file, err := os.Open(filename)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening %s: %w", filename, err)
+	}
+	defer file.Close()
+
+	var records []StoredArticle
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		var record StoredArticle
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", filename, err)
+		}
+		records = append(records, record)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("scanning %s: %w", filename, err)
+	}
+	return records, nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestNewStoredArticleLatency(t *testing.T) {
+	published := time.Now().Add(-2 * time.Hour).UTC()
+	article := &Article{Link: "https://example.com/post", Published: published.Format(time.RFC3339)}
+
+	tests := []struct {
+		name     string
+		notified bool
+	}{
+		{"delivered", true},
+		{"queued, held or batched", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			record := newStoredArticle(article, "https://example.com/feed", tt.notified)
+			if record.Notified != tt.notified {
+				t.Errorf("Notified = %v, want %v", record.Notified, tt.notified)
+			}
+			if hasLatency := record.LatencySeconds > 0; hasLatency != tt.notified {
+				t.Errorf("LatencySeconds = %v, want it set only when notified", record.LatencySeconds)
+			}
+		})
+	}
+}
+
+func TestMarkStoredDelivered(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), articleStoreFileName)
+	published := time.Date(2025, 1, 15, 8, 0, 0, 0, time.UTC)
+	ingested := time.Date(2025, 1, 15, 9, 0, 0, 0, time.UTC)
+	delivered := time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	records := []StoredArticle{
+		{Link: "https://example.com/sent", Published: published.Format(time.RFC3339), Notified: true, LatencySeconds: 3600, IngestedAt: ingested},
+		{Link: "https://example.com/queued", Published: published.Format(time.RFC3339), IngestedAt: ingested},
+		{Link: "https://example.com/undated", IngestedAt: ingested},
+		{Link: "https://example.com/other", Published: published.Format(time.RFC3339), IngestedAt: ingested},
+	}
+	if err := appendStoredArticles(filename, records...); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := markStoredDelivered(filename, []string{
+		"https://example.com/sent",
+		"https://example.com/queued",
+		"https://example.com/undated",
+	}, delivered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updated) != 2 {
+		t.Fatalf("updated %d records, want the queued and undated ones", len(updated))
+	}
+
+	stored, err := readStoredArticles(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		notified bool
+		latency  float64
+	}{
+		// Already delivered at ingestion, left alone
+		{true, 3600},
+		{true, 4 * 3600},
+		{true, 0},
+		{false, 0},
+	}
+	for i, w := range want {
+		if stored[i].Notified != w.notified || stored[i].LatencySeconds != w.latency {
+			t.Errorf("%s: notified %v latency %v, want %v %v", stored[i].Link,
+				stored[i].Notified, stored[i].LatencySeconds, w.notified, w.latency)
+		}
+	}
+
+	// Marking again changes nothing
+	if updated, err := markStoredDelivered(filename, []string{"https://example.com/queued"}, delivered.Add(time.Hour)); err != nil || len(updated) != 0 {
+		t.Errorf("second mark updated %d records, err %v", len(updated), err)
+	}
+}
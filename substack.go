@@ -0,0 +1,52 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mmcdole/gofeed"
+)
+
+// substackPaywallMarkers appear in the truncated content of paid-only posts
+var substackPaywallMarkers = []string{
+	"paywall",
+	"this post is for paid subscribers",
+	"keep reading with a 7-day free trial",
+	"subscribe to keep reading",
+}
+
+// isSubstackFeed recognizes Substack feeds, including custom domains
+func isSubstackFeed(feedURL string, feed *gofeed.Feed) bool {
+	return strings.HasSuffix(getDomain(feedURL), "substack.com") || strings.Contains(strings.ToLower(feed.Generator), "substack")
+}
+
+// prepareSubstackItems flags paid-only posts and, since Substack's
+// description is only the subtitle, matches against the post text as well
+func prepareSubstackItems(items []*gofeed.Item) {
+	for _, item := range items {
+		content := strings.ToLower(item.Content)
+		for _, marker := range substackPaywallMarkers {
+			if strings.Contains(content, marker) {
+				if item.Custom == nil {
+					item.Custom = make(map[string]string)
+				}
+				item.Custom["paid"] = "true"
+				break
+			}
+		}
+
+		if item.Content == "" {
+			continue
+		}
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(item.Content))
+		if err != nil {
+			continue
+		}
+		item.Description = strings.TrimSpace(item.Description + " " + strings.Join(strings.Fields(doc.Text()), " "))
+	}
+}
+
+// isPaidOnly reports whether prepareSubstackItems flagged the item
+func isPaidOnly(item *gofeed.Item) bool {
+	return item.Custom != nil && item.Custom["paid"] == "true"
+}
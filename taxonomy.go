@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultTaxonomyFileName is read when hunter.yaml names no taxonomy file
+const defaultTaxonomyFileName = "taxonomy.yaml"
+
+// readTaxonomy reads a taxonomy file mapping canonical tags to their aliases:
+//
+//	ssrf: [SSRF, server side request forgery, server-side request forgery]
+//
+// A missing default file is not an error.
+func readTaxonomy(filename string) (map[string][]string, error) {
+	taxonomy := make(map[string][]string)
+
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) && filename == defaultTaxonomyFileName {
+		return taxonomy, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", filename, err)
+	}
+	if err := yaml.Unmarshal(data, &taxonomy); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+	return taxonomy, nil
+}
+
+// mergeTaxonomy turns each canonical tag into a category holding its aliases,
+// adding them to a category of the same name when hunter.yaml defines one. New
+// categories keep the thread of a built-in keyword of the same name.
+func mergeTaxonomy(s *Settings, taxonomy map[string][]string) {
+	if len(taxonomy) == 0 {
+		return
+	}
+	if s.Categories == nil {
+		s.Categories = make(map[string]Category)
+	}
+	for tag, aliases := range taxonomy {
+		category, exists := s.Categories[tag]
+		if !exists {
+			category.Thread = keywords[tag]
+			category.Terms = []string{tag}
+		}
+		category.Terms = append(append([]string(nil), category.Terms...), aliases...)
+		s.Categories[tag] = category
+	}
+}
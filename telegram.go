@@ -0,0 +1,121 @@
+package main
+
+import (
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// Telegram rejects photo captions longer than this
+const telegramCaptionLimit = 1024
+
+// TelegramSettings tunes how notifications look in the channel
+type TelegramSettings struct {
+	// Previews sends articles with their og:image via sendPhoto, or with a
+	// large link preview when there is no image or the text is too long
+	Previews bool `yaml:"previews"`
+
+	// ParseMode formats articles as HTML or MarkdownV2: bold titles,
+	// clickable links and #hashtag tags. Empty keeps plain text.
+	ParseMode string `yaml:"parse_mode"`
+
+	// Buttons attaches Open, Archived copy, Save and Not relevant buttons;
+	// "feedback poll" records the presses and "feedback report" sums them up
+	Buttons bool `yaml:"buttons"`
+
+	// AutoTopics creates a forum topic for each category without a thread;
+	// the bot needs the manage topics right. Created topics are kept in topics.json.
+	AutoTopics bool `yaml:"auto_topics"`
+
+	// Proxy routes only api.telegram.org calls (http://, https://, socks5://
+	// or socks5h://), for networks where the Bot API is blocked
+	Proxy string `yaml:"proxy"`
+
+	// Burst groups a feed's notifications into one list per tag when it
+	// yields more than this many at once (a new feed's first run, say),
+	// instead of tripping flood limits; 0 means 10, negative never groups
+	Burst int `yaml:"burst"`
+
+	// Templates replace the built-in article layout
+	Templates TemplateSettings `yaml:"templates"`
+}
+
+// itemImage returns the image a feed item carries itself, if any
+func itemImage(item *gofeed.Item) string {
+	if item.Image != nil && item.Image.URL != "" {
+		return item.Image.URL
+	}
+	for _, enclosure := range item.Enclosures {
+		if enclosure != nil && strings.HasPrefix(enclosure.Type, "image/") {
+			return enclosure.URL
+		}
+	}
+	return ""
+}
+
+// Telegram parse modes for article notifications; plain text is the default
+const (
+	parseModeHTML       = "HTML"
+	parseModeMarkdownV2 = "MarkdownV2"
+)
+
+// markdownV2Replacer escapes every character MarkdownV2 reserves
+var markdownV2Replacer = strings.NewReplacer(
+	`\`, `\\`, "_", `\_`, "*", `\*`, "[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`,
+	"~", `\~`, "`", "\\`", ">", `\>`, "#", `\#`, "+", `\+`, "-", `\-`, "=", `\=`,
+	"|", `\|`, "{", `\{`, "}", `\}`, ".", `\.`, "!", `\!`,
+)
+
+// markdownV2URLReplacer escapes the characters MarkdownV2 reserves inside (...)
+var markdownV2URLReplacer = strings.NewReplacer(`\`, `\\`, ")", `\)`)
+
+// messageFormatter renders message parts for a parse mode, escaping the text
+type messageFormatter struct {
+	mode string
+}
+
+func (f messageFormatter) escape(text string) string {
+	switch f.mode {
+	case parseModeHTML:
+		return html.EscapeString(text)
+	case parseModeMarkdownV2:
+		return markdownV2Replacer.Replace(text)
+	}
+	return text
+}
+
+func (f messageFormatter) bold(text string) string {
+	switch f.mode {
+	case parseModeHTML:
+		return "<b>" + f.escape(text) + "</b>"
+	case parseModeMarkdownV2:
+		return "*" + f.escape(text) + "*"
+	}
+	return text
+}
+
+// link renders clickable text; plain text shows the URL itself
+func (f messageFormatter) link(text, target string) string {
+	switch f.mode {
+	case parseModeHTML:
+		return `<a href="` + html.EscapeString(target) + `">` + f.escape(text) + "</a>"
+	case parseModeMarkdownV2:
+		return "[" + f.escape(text) + "](" + markdownV2URLReplacer.Replace(target) + ")"
+	}
+	return target
+}
+
+// hashtag renders a tag as a searchable #hashtag in formatted modes
+func (f messageFormatter) hashtag(tag string) string {
+	if f.mode == "" {
+		return tag
+	}
+	return f.escape("#" + strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, tag))
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mmcdole/gofeed"
+)
+
+// telegramSourcePrefix marks data.txt shorthand for public Telegram channels
+// read as sources, e.g. "telegram:@somechannel,@other". Posts are read from the
+// channel's public web preview (t.me/s/...), which needs neither a bot in the
+// channel nor a user session; posts linking out become articles.
+const (
+	telegramSourcePrefix = "telegram:"
+	telegramPreviewBase  = "https://t.me/s/"
+)
+
+// expandTelegramSourceEntry turns a telegram: line into web preview URLs
+func expandTelegramSourceEntry(entry string) ([]string, error) {
+	var feeds []string
+	for _, channel := range strings.Split(strings.TrimPrefix(entry, telegramSourcePrefix), ",") {
+		channel = strings.TrimPrefix(strings.TrimSpace(channel), "@")
+		if channel == "" {
+			continue
+		}
+		if strings.ContainsAny(channel, "/?# ") {
+			return nil, fmt.Errorf("malformed telegram entry: %q", entry)
+		}
+		feeds = append(feeds, telegramPreviewBase+channel)
+	}
+	if len(feeds) == 0 {
+		return nil, fmt.Errorf("telegram entry %q names no channels", entry)
+	}
+	return feeds, nil
+}
+
+// isTelegramChannelSource recognizes channel web previews
+func isTelegramChannelSource(feedURL string) bool {
+	return strings.HasPrefix(feedURL, telegramPreviewBase)
+}
+
+// parseTelegramChannel turns the posts of a channel preview page into items
+// pointing at the first outbound link of each post
+func parseTelegramChannel(body []byte) ([]*gofeed.Item, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("parsing channel page: %w", err)
+	}
+
+	var items []*gofeed.Item
+	doc.Find(".tgme_widget_message[data-post]").Each(func(_ int, post *goquery.Selection) {
+		text := post.Find(".tgme_widget_message_text").First()
+
+		var link string
+		text.Find("a[href]").EachWithBreak(func(_ int, a *goquery.Selection) bool {
+			href := a.AttrOr("href", "")
+			if strings.HasPrefix(href, "http") && getDomain(href) != "t.me" {
+				link = href
+				return false
+			}
+			return true
+		})
+		preview := post.Find(".tgme_widget_message_link_preview").First()
+		if link == "" {
+			link = preview.AttrOr("href", "")
+		}
+		if link == "" {
+			return
+		}
+
+		content := strings.Join(strings.Fields(text.Text()), " ")
+		title := strings.TrimSpace(preview.Find(".link_preview_title").Text())
+		if title == "" {
+			title = tweetTitle(content)
+		}
+
+		items = append(items, &gofeed.Item{
+			Title:       title,
+			Description: content + " " + strings.TrimSpace(preview.Find(".link_preview_description").Text()),
+			Link:        link,
+			GUID:        "tg-" + post.AttrOr("data-post", ""),
+			Published:   post.Find("time[datetime]").First().AttrOr("datetime", ""),
+		})
+	})
+	return items, nil
+}
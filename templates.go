@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// TemplateSettings replaces the built-in article layout with Go templates
+// (text/template). A category's template wins over the chat's, which wins
+// over the default. Templates see every Article field plus .Tag, .ReadLink
+// (cleaned, through freedium for Medium) and .ReadingTime; with a parse mode,
+// wrap article text in escape, bold, link or hashtag so it renders safely.
+type TemplateSettings struct {
+	Default    string            `yaml:"default"`
+	Chats      map[string]string `yaml:"chats"`
+	Categories map[string]string `yaml:"categories"`
+}
+
+// templateData is what a message template is executed with
+type templateData struct {
+	*Article
+	Tag         string
+	ReadLink    string
+	ReadingTime string
+}
+
+// parsedTemplates caches templates by source; a nil entry failed to parse
+// and was already reported
+var parsedTemplates = struct {
+	mu     sync.Mutex
+	parsed map[string]*template.Template
+}{parsed: make(map[string]*template.Template)}
+
+// templateFuncs exposes the parse mode's formatting helpers to templates
+func templateFuncs(f messageFormatter) template.FuncMap {
+	return template.FuncMap{
+		"escape":  f.escape,
+		"bold":    f.bold,
+		"link":    f.link,
+		"hashtag": f.hashtag,
+		"join":    strings.Join,
+	}
+}
+
+func parseMessageTemplate(source string) (*template.Template, error) {
+	// Parsing only checks the function names, so any formatter will do
+	return template.New("message").Funcs(templateFuncs(messageFormatter{})).Parse(source)
+}
+
+// templateFor picks the template source for a tag sent to a chat
+func templateFor(chatID, tag string) string {
+	cfg := settings.Telegram.Templates
+	if source, exists := cfg.Categories[tag]; exists {
+		return source
+	}
+	if source, exists := cfg.Chats[chatID]; exists {
+		return source
+	}
+	return cfg.Default
+}
+
+// renderTemplate formats an article with the template configured for the
+// chat and tag; ok is false when none applies or it fails
+func renderTemplate(article *Article, tag, chatID, parseMode string) (string, bool) {
+	source := templateFor(chatID, tag)
+	if source == "" {
+		return "", false
+	}
+
+	parsedTemplates.mu.Lock()
+	tmpl, exists := parsedTemplates.parsed[source]
+	if !exists {
+		var err error
+		if tmpl, err = parseMessageTemplate(source); err != nil {
+			printError(fmt.Sprintf("parsing message template: %v", err))
+		}
+		parsedTemplates.parsed[source] = tmpl
+	}
+	parsedTemplates.mu.Unlock()
+	if tmpl == nil {
+		return "", false
+	}
+
+	data := templateData{
+		Article:     article,
+		Tag:         tag,
+		ReadLink:    readerLink(article.Link),
+		ReadingTime: readingTime(article.WordCount),
+	}
+	var text strings.Builder
+	if err := template.Must(tmpl.Clone()).Funcs(templateFuncs(messageFormatter{mode: parseMode})).Execute(&text, data); err != nil {
+		printError(fmt.Sprintf("rendering message template for %s: %v", tag, err))
+		return "", false
+	}
+	return strings.TrimSpace(text.String()), true
+}
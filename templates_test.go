@@ -0,0 +1,88 @@
+package main
+
+import "testing"
+
+func TestTemplateFor(t *testing.T) {
+	useSettings(t, Settings{Telegram: TelegramSettings{Templates: TemplateSettings{
+		Default:    "default",
+		Chats:      map[string]string{"-100": "chat"},
+		Categories: map[string]string{"xss": "category"},
+	}}})
+
+	tests := []struct {
+		chatID, tag, want string
+	}{
+		{"-100", "xss", "category"},
+		{"-200", "xss", "category"},
+		{"-100", "idor", "chat"},
+		{"-200", "idor", "default"},
+	}
+	for _, tt := range tests {
+		if got := templateFor(tt.chatID, tt.tag); got != tt.want {
+			t.Errorf("templateFor(%q, %q) = %q, want %q", tt.chatID, tt.tag, got, tt.want)
+		}
+	}
+}
+
+func TestRenderTemplate(t *testing.T) {
+	article := &Article{
+		Title:     "XSS in <script> tags",
+		Link:      "https://example.com/post?utm_source=feed",
+		WordCount: 400,
+	}
+
+	tests := []struct {
+		name      string
+		templates TemplateSettings
+		parseMode string
+		want      string
+		wantOK    bool
+	}{
+		{
+			name:   "no template falls back to the built-in layout",
+			wantOK: false,
+		},
+		{
+			name:      "plain text",
+			templates: TemplateSettings{Default: "{{.Title}} ({{.ReadingTime}}) #{{.Tag}}\n"},
+			want:      "XSS in <script> tags (2 min read) #xss",
+			wantOK:    true,
+		},
+		{
+			name:      "HTML helpers escape",
+			templates: TemplateSettings{Default: `{{bold .Title}} {{link "read" .ReadLink}}`},
+			parseMode: parseModeHTML,
+			want:      `<b>XSS in &lt;script&gt; tags</b> <a href="https://example.com/post">read</a>`,
+			wantOK:    true,
+		},
+		{
+			name:      "category template over a broken default",
+			templates: TemplateSettings{Default: "{{.Title", Categories: map[string]string{"xss": "{{.Tag}}"}},
+			want:      "xss",
+			wantOK:    true,
+		},
+		{
+			name:      "parse error falls back",
+			templates: TemplateSettings{Default: "{{.Title"},
+			wantOK:    false,
+		},
+		{
+			name:      "execution error falls back",
+			templates: TemplateSettings{Default: "{{.Missing}}"},
+			wantOK:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			useSettings(t, Settings{Telegram: TelegramSettings{Templates: tt.templates}})
+
+			got, ok := renderTemplate(article, "xss", "-100", tt.parseMode)
+			if ok != tt.wantOK {
+				t.Fatalf("renderTemplate ok = %v, want %v", ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Errorf("renderTemplate = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
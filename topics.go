@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/fatih/color"
+)
+
+// topicsFileName records forum topics created for tags without a thread,
+// keyed by chat and then by tag
+const topicsFileName = "topics.json"
+
+// forumTopics holds the created topics between loading and saving them
+var forumTopics = struct {
+	mu     sync.Mutex
+	topics map[string]map[string]string
+}{topics: make(map[string]map[string]string)}
+
+func readTopics(filename string) (map[string]map[string]string, error) {
+	topics := make(map[string]map[string]string)
+
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return topics, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", filename, err)
+	}
+	if err := json.Unmarshal(data, &topics); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", filename, err)
+	}
+	return topics, nil
+}
+
+func writeTopics(filename string, topics map[string]map[string]string) error {
+	data, err := json.MarshalIndent(topics, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding topics: %w", err)
+	}
+	if err := os.WriteFile(filename, data, 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", filename, err)
+	}
+	return nil
+}
+
+// storedTopic returns the topic created earlier for a tag in a chat
+func storedTopic(chatID, tag string) (string, bool) {
+	forumTopics.mu.Lock()
+	defer forumTopics.mu.Unlock()
+	thread, exists := forumTopics.topics[chatID][tag]
+	return thread, exists
+}
+
+// ensureTopic returns the tag's topic in the chat, creating it with
+// createForumTopic the first time. The lock is held across the call so
+// concurrent feeds do not create the same topic twice.
+func ensureTopic(ctx context.Context, botToken, chatID, tag string) (string, error) {
+	forumTopics.mu.Lock()
+	defer forumTopics.mu.Unlock()
+
+	if thread, exists := forumTopics.topics[chatID][tag]; exists {
+		return thread, nil
+	}
+
+	var topic struct {
+		MessageThreadID int `json:"message_thread_id"`
+	}
+	params := url.Values{"chat_id": {chatID}, "name": {tag}}
+	if err := telegramGet(ctx, botToken, "createForumTopic", params, &topic); err != nil {
+		return "", err
+	}
+
+	thread := strconv.Itoa(topic.MessageThreadID)
+	if forumTopics.topics[chatID] == nil {
+		forumTopics.topics[chatID] = make(map[string]string)
+	}
+	forumTopics.topics[chatID][tag] = thread
+	printStatus(fmt.Sprintf("Created forum topic %q (thread %s)", tag, thread), color.FgGreen)
+	return thread, nil
+}
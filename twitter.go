@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/mmcdole/gofeed"
+)
+
+// twitterPrefix marks data.txt shorthand for X accounts and lists:
+//
+//	x:@someone,@other     posts of each account
+//	x:list:1234567890     posts of a list
+//
+// Posts are read from the X API when twitter.bearer_token is set, otherwise
+// from the RSS of the configured Nitter instance. Only posts linking out to
+// something become articles, with the link as the article.
+const (
+	twitterPrefix     = "x:"
+	twitterListPrefix = "list:"
+	twitterAPIBase    = "https://api.x.com/2/"
+)
+
+// TwitterSettings selects how X posts are read
+type TwitterSettings struct {
+	BearerToken string `yaml:"bearer_token"`
+	Nitter      string `yaml:"nitter"`
+}
+
+// expandTwitterEntry turns an x: line into API or Nitter URLs
+func expandTwitterEntry(entry string) ([]string, error) {
+	spec := strings.TrimSpace(strings.TrimPrefix(entry, twitterPrefix))
+	nitter := strings.TrimSuffix(settings.Twitter.Nitter, "/")
+	if settings.Twitter.BearerToken == "" && nitter == "" {
+		return nil, fmt.Errorf("%s needs twitter.bearer_token or twitter.nitter in %s", entry, settingsFileName)
+	}
+
+	if list, found := strings.CutPrefix(spec, twitterListPrefix); found {
+		if list == "" || strings.ContainsAny(list, "/?# ") {
+			return nil, fmt.Errorf("malformed x entry: %q", entry)
+		}
+		if settings.Twitter.BearerToken != "" {
+			return []string{twitterAPIBase + "lists/" + list + "/tweets?" + twitterAPIFields()}, nil
+		}
+		return []string{nitter + "/i/lists/" + list + "/rss"}, nil
+	}
+
+	var feeds []string
+	for _, account := range strings.Split(spec, ",") {
+		account = strings.TrimPrefix(strings.TrimSpace(account), "@")
+		if account == "" {
+			continue
+		}
+		if strings.ContainsAny(account, "/?# ") {
+			return nil, fmt.Errorf("malformed x entry: %q", entry)
+		}
+		if settings.Twitter.BearerToken != "" {
+			query := url.Values{}
+			query.Set("query", "from:"+account+" has:links -is:retweet")
+			feeds = append(feeds, twitterAPIBase+"tweets/search/recent?"+query.Encode()+"&"+twitterAPIFields())
+		} else {
+			feeds = append(feeds, nitter+"/"+account+"/rss")
+		}
+	}
+	if len(feeds) == 0 {
+		return nil, fmt.Errorf("x entry %q names no accounts", entry)
+	}
+	return feeds, nil
+}
+
+func twitterAPIFields() string {
+	return "tweet.fields=created_at,entities&expansions=author_id&user.fields=username&max_results=50"
+}
+
+// isTwitterAPI recognizes X API v2 URLs, which are read with the bearer token
+func isTwitterAPI(feedURL string) bool {
+	return strings.HasPrefix(feedURL, twitterAPIBase)
+}
+
+// isNitterFeed recognizes RSS from the configured Nitter instance
+func isNitterFeed(feedURL string) bool {
+	nitter := settings.Twitter.Nitter
+	return nitter != "" && strings.HasPrefix(feedURL, strings.TrimSuffix(nitter, "/")+"/")
+}
+
+// isTwitterHost reports links that stay on X or its mirrors
+func isTwitterHost(link string) bool {
+	domain := getDomain(link)
+	return domain == "x.com" || domain == "twitter.com" || domain == "t.co" ||
+		domain == getDomain(settings.Twitter.Nitter)
+}
+
+type twitterAPIResponse struct {
+	Data []struct {
+		ID        string `json:"id"`
+		Text      string `json:"text"`
+		AuthorID  string `json:"author_id"`
+		CreatedAt string `json:"created_at"`
+		Entities  struct {
+			URLs []struct {
+				ExpandedURL string `json:"expanded_url"`
+				UnwoundURL  string `json:"unwound_url"`
+				Title       string `json:"title"`
+			} `json:"urls"`
+		} `json:"entities"`
+	} `json:"data"`
+	Includes struct {
+		Users []struct {
+			ID       string `json:"id"`
+			Username string `json:"username"`
+		} `json:"users"`
+	} `json:"includes"`
+}
+
+// parseTwitterAPIResponse keeps posts linking outside X, one item per post
+func parseTwitterAPIResponse(body []byte) ([]*gofeed.Item, error) {
+	var response twitterAPIResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("unmarshaling X API response: %w", err)
+	}
+
+	usernames := make(map[string]string)
+	for _, user := range response.Includes.Users {
+		usernames[user.ID] = user.Username
+	}
+
+	var items []*gofeed.Item
+	for _, post := range response.Data {
+		for _, link := range post.Entities.URLs {
+			target := firstNonEmpty(link.UnwoundURL, link.ExpandedURL)
+			if target == "" || isTwitterHost(target) {
+				continue
+			}
+			item := &gofeed.Item{
+				Title:       firstNonEmpty(link.Title, tweetTitle(post.Text)),
+				Description: post.Text,
+				Link:        target,
+				GUID:        "x-" + post.ID,
+				Published:   post.CreatedAt,
+			}
+			if username := usernames[post.AuthorID]; username != "" {
+				item.Author = &gofeed.Person{Name: "@" + username}
+			}
+			items = append(items, item)
+			break
+		}
+	}
+	return items, nil
+}
+
+// extractTweetLinks rewrites Nitter items to point at the first outbound link
+// in the post and drops posts without one
+func extractTweetLinks(items []*gofeed.Item) []*gofeed.Item {
+	var linked []*gofeed.Item
+	for _, item := range items {
+		doc, err := goquery.NewDocumentFromReader(strings.NewReader(item.Description))
+		if err != nil {
+			continue
+		}
+		var target string
+		doc.Find("a[href]").EachWithBreak(func(_ int, sel *goquery.Selection) bool {
+			href := sel.AttrOr("href", "")
+			if strings.HasPrefix(href, "http") && !isTwitterHost(href) {
+				target = href
+				return false
+			}
+			return true
+		})
+		if target == "" {
+			continue
+		}
+
+		text := strings.Join(strings.Fields(doc.Text()), " ")
+		item.GUID = firstNonEmpty(item.GUID, item.Link)
+		item.Link = target
+		item.Title = tweetTitle(firstNonEmpty(item.Title, text))
+		item.Description = text
+		linked = append(linked, item)
+	}
+	return linked
+}
+
+// tweetTitle shortens post text to a notification title
+func tweetTitle(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if runes := []rune(text); len(runes) > 120 {
+		return string(runes[:117]) + "..."
+	}
+	return text
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"net/http"
+	"sync"
+)
+
+// defaultUserAgent identifies the hunter; Go's own agent is blocked by some blogs
+const defaultUserAgent = "Mozilla/5.0 (compatible; writeup-hunter/1.0; +https://github.com/jinnyohjinny/writeup-hunter)"
+
+// userAgentRotation hands out the configured agents round-robin
+var userAgentRotation struct {
+	mu   sync.Mutex
+	next int
+}
+
+// userAgentFor picks the agent for a request: the feed's own, then the next
+// one in the rotation, then the single configured agent, then the default
+func userAgentFor(rawURL string) string {
+	if agent := feedOptions(rawURL).UserAgent; agent != "" {
+		return agent
+	}
+	// Reddit throttles browser and generic agents; it asks for a descriptive one
+	if isRedditListing(rawURL) {
+		return defaultUserAgent
+	}
+	if len(settings.UserAgents) > 0 {
+		userAgentRotation.mu.Lock()
+		defer userAgentRotation.mu.Unlock()
+		agent := settings.UserAgents[userAgentRotation.next%len(settings.UserAgents)]
+		userAgentRotation.next++
+		return agent
+	}
+	if settings.UserAgent != "" {
+		return settings.UserAgent
+	}
+	return defaultUserAgent
+}
+
+// setUserAgent applies userAgentFor to an outgoing fetch request
+func setUserAgent(req *http.Request) {
+	req.Header.Set("User-Agent", userAgentFor(req.URL.String()))
+}
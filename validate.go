@@ -0,0 +1,479 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/andybalholm/cascadia"
+	"github.com/fatih/color"
+)
+
+const (
+	telegramMethodTemplate = "https://api.telegram.org/bot%s/%s"
+	keywordsFileName       = "keywords.txt"
+	validateTimeout        = 15 * time.Second
+)
+
+// telegramResponse is the common envelope of Bot API responses
+type telegramResponse struct {
+	OK          bool            `json:"ok"`
+	Description string          `json:"description"`
+	Result      json.RawMessage `json:"result"`
+}
+
+// runConfig handles "config validate", exiting with exitConfigError when
+// there are problems so CI can tell them from a crash
+func runConfig(args []string) int {
+	if len(args) == 0 || args[0] != "validate" {
+		printError("usage: config validate")
+		return exitConfigError
+	}
+
+	problems := validateConfig()
+	if len(problems) == 0 {
+		printStatus("Configuration is valid", color.FgGreen)
+		return exitClean
+	}
+
+	for _, problem := range problems {
+		printError(problem)
+	}
+	printStatus(fmt.Sprintf("%d problem(s) found", len(problems)), color.FgRed)
+	return exitConfigError
+}
+
+// validateConfig collects every problem instead of stopping at the first one
+func validateConfig() []string {
+	var problems []string
+
+	s, err := loadSettings(settingsFileName)
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+	// Source shorthand such as x: expands according to the settings, and the
+	// Telegram checks go through the configured proxy and HTTP client
+	settings = s
+	// Invalid proxy or HTTP settings are reported by validateSettings below
+	if configureProxy() == nil {
+		configureHTTPClient()
+	}
+
+	problems = append(problems, validateFeedList(urlsFileName)...)
+	problems = append(problems, validateKeywords(keywordsFileName)...)
+	problems = append(problems, validateSettings(s)...)
+
+	// Profile thread lookups need the resolved categories; a failure to
+	// resolve them is reported by validateSettings
+	if terms, threads, disabled, err := resolveCategories(s.Categories); err == nil {
+		categoryOfTerm, categoryThreads, disabledTerms = terms, threads, disabled
+	}
+	targets, targetProblems := telegramTargets(s, getEnvForEnvironment("TELEGRAM_CHANNEL_ID"))
+	problems = append(problems, targetProblems...)
+	problems = append(problems, validateTelegram(getEnvForEnvironment("TELEGRAM_BOT_TOKEN"), targets)...)
+
+	return problems
+}
+
+func validateFeedList(filename string) []string {
+	entries, err := readURLs(filename)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	var problems []string
+	seen := make(map[string]struct{})
+	for _, entry := range entries {
+		target := entry
+		if strings.HasPrefix(entry, remoteListPrefix) {
+			list, err := parseRemoteList(entry)
+			if err != nil {
+				problems = append(problems, err.Error())
+				continue
+			}
+			target = list.URL
+		}
+		feeds, err := expandShorthand(target)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", filename, err))
+			continue
+		}
+		target = feeds[0]
+
+		switch {
+		case isIMAPSource(target):
+			if err := validateIMAPSource(target); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", filename, err))
+			}
+		case isPluginSource(target):
+			if err := validatePluginSource(target); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", filename, err))
+			}
+		default:
+			if u, err := url.Parse(target); err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+				problems = append(problems, fmt.Sprintf("%s: invalid feed URL %q", filename, target))
+			}
+		}
+		if _, exists := seen[entry]; exists {
+			problems = append(problems, fmt.Sprintf("%s: duplicate entry %q", filename, entry))
+		}
+		seen[entry] = struct{}{}
+	}
+	return problems
+}
+
+// validateKeywords reports case-insensitive duplicates in the keyword file and
+// in the built-in keyword table
+func validateKeywords(filename string) []string {
+	var problems []string
+
+	file, err := os.Open(filename)
+	if err != nil && !os.IsNotExist(err) {
+		problems = append(problems, fmt.Sprintf("opening %s: %v", filename, err))
+	}
+	if err == nil {
+		defer file.Close()
+		seen := make(map[string]int)
+		scanner := bufio.NewScanner(file)
+		for line := 1; scanner.Scan(); line++ {
+			keyword := strings.ToLower(strings.TrimSpace(scanner.Text()))
+			if keyword == "" {
+				continue
+			}
+			if first, exists := seen[keyword]; exists {
+				problems = append(problems, fmt.Sprintf("%s:%d: duplicate keyword %q (first on line %d)", filename, line, keyword, first))
+				continue
+			}
+			seen[keyword] = line
+		}
+	}
+
+	byLower := make(map[string][]string)
+	for keyword := range keywords {
+		lower := strings.ToLower(keyword)
+		byLower[lower] = append(byLower[lower], keyword)
+	}
+	for _, variants := range byLower {
+		if len(variants) > 1 {
+			sort.Strings(variants)
+			problems = append(problems, fmt.Sprintf("built-in keywords differ only by case: %s", strings.Join(variants, ", ")))
+		}
+	}
+
+	return problems
+}
+
+func validateSettings(s Settings) []string {
+	var problems []string
+
+	if _, _, _, err := resolveCategories(s.Categories); err != nil {
+		problems = append(problems, fmt.Sprintf("%s: %v", settingsFileName, err))
+	}
+	for _, term := range s.Exclude {
+		if pattern, isRegex := regexKeyword(term); isRegex {
+			if _, err := compileKeyword(pattern); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: exclude: %v", settingsFileName, err))
+			}
+		}
+	}
+	for keyword, options := range s.KeywordOptions {
+		for _, term := range options.Exclude {
+			if pattern, isRegex := regexKeyword(term); isRegex {
+				if _, err := compileKeyword(pattern); err != nil {
+					problems = append(problems, fmt.Sprintf("%s: keyword_options.%s.exclude: %v", settingsFileName, keyword, err))
+				}
+			}
+		}
+	}
+	for name, category := range s.Categories {
+		for _, term := range append(append([]string(nil), category.Terms...), category.Exclude...) {
+			if pattern, isRegex := regexKeyword(term); isRegex {
+				if _, err := compileKeyword(pattern); err != nil {
+					problems = append(problems, fmt.Sprintf("%s: categories.%s: %v", settingsFileName, name, err))
+				}
+			}
+		}
+	}
+	for name, profile := range s.Profiles {
+		for term := range profile.Keywords {
+			if pattern, isRegex := regexKeyword(term); isRegex {
+				if _, err := compileKeyword(pattern); err != nil {
+					problems = append(problems, fmt.Sprintf("%s: profiles.%s.keywords: %v", settingsFileName, name, err))
+				}
+			}
+		}
+	}
+	for name, program := range s.Programs {
+		for _, term := range program.Aliases {
+			if pattern, isRegex := regexKeyword(term); isRegex {
+				if _, err := compileKeyword(pattern); err != nil {
+					problems = append(problems, fmt.Sprintf("%s: programs.%s: %v", settingsFileName, name, err))
+				}
+			}
+		}
+	}
+	if s.Severity.Min != "" && severityRank(s.Severity.Min) < 0 {
+		problems = append(problems, fmt.Sprintf("%s: severity.min: unknown level %q", settingsFileName, s.Severity.Min))
+	}
+	for level, terms := range s.Severity.Terms {
+		if severityRank(level) < 0 {
+			problems = append(problems, fmt.Sprintf("%s: severity.terms: unknown level %q", settingsFileName, level))
+		}
+		for _, term := range terms {
+			if pattern, isRegex := regexKeyword(term); isRegex {
+				if _, err := compileKeyword(pattern); err != nil {
+					problems = append(problems, fmt.Sprintf("%s: severity.terms.%s: %v", settingsFileName, level, err))
+				}
+			}
+		}
+	}
+	for level := range s.Severity.Threads {
+		if severityRank(level) < 0 {
+			problems = append(problems, fmt.Sprintf("%s: severity.threads: unknown level %q", settingsFileName, level))
+		}
+	}
+	if mode := s.Telegram.ParseMode; mode != "" && mode != parseModeHTML && mode != parseModeMarkdownV2 {
+		problems = append(problems, fmt.Sprintf("%s: telegram.parse_mode must be HTML or MarkdownV2", settingsFileName))
+	}
+	if mode := s.Digest.Mode; mode != "" && mode != digestModeRun && mode != digestModeDaily {
+		problems = append(problems, fmt.Sprintf("%s: digest.mode must be run or daily", settingsFileName))
+	}
+	if s.Digest.Hour < 0 || s.Digest.Hour > 23 {
+		problems = append(problems, fmt.Sprintf("%s: digest.hour must be between 0 and 23", settingsFileName))
+	}
+	for tag, route := range s.Routes {
+		if route.ChatID == "" {
+			problems = append(problems, fmt.Sprintf("%s: routes.%s: chat_id is required", settingsFileName, tag))
+		}
+	}
+	if email := s.Failover.Email; email.Host != "" && (email.From == "" || len(email.To) == 0) {
+		problems = append(problems, fmt.Sprintf("%s: failover.email needs from and to", settingsFileName))
+	}
+	if (s.QuietHours.Start == "") != (s.QuietHours.End == "") {
+		problems = append(problems, fmt.Sprintf("%s: quiet_hours needs both start and end", settingsFileName))
+	}
+	for _, bound := range []string{s.QuietHours.Start, s.QuietHours.End} {
+		if bound == "" {
+			continue
+		}
+		if _, err := parseQuietTime(bound); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: quiet_hours: %v", settingsFileName, err))
+		}
+	}
+	if format := s.Snapshot.Format; format != "" && format != snapshotFormatHTML && format != snapshotFormatPDF {
+		problems = append(problems, fmt.Sprintf("%s: snapshot.format must be html or pdf", settingsFileName))
+	}
+	switch s.Classifier.Provider {
+	case "":
+	case classifierOpenAI, classifierOllama:
+		if s.Classifier.Model == "" {
+			problems = append(problems, fmt.Sprintf("%s: classifier: model is required", settingsFileName))
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("%s: classifier: unknown provider %q", settingsFileName, s.Classifier.Provider))
+	}
+	if _, err := newHTTPTransport(s.HTTP); err != nil {
+		problems = append(problems, fmt.Sprintf("%s: %v", settingsFileName, err))
+	}
+	claimed := make(map[string]string)
+	for name, category := range s.Categories {
+		for _, term := range category.Terms {
+			if other, exists := claimed[strings.ToLower(term)]; exists && other != name {
+				problems = append(problems, fmt.Sprintf("%s: term %q appears in categories %s and %s", settingsFileName, term, other, name))
+			}
+			claimed[strings.ToLower(term)] = name
+		}
+	}
+	if _, exists := dedupStrategies[s.Dedup]; s.Dedup != "" && !exists {
+		problems = append(problems, fmt.Sprintf("%s: unknown dedup strategy %q", settingsFileName, s.Dedup))
+	}
+	if s.Proxy != "" {
+		if _, err := parseProxyURL(s.Proxy); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: proxy: %v", settingsFileName, err))
+		}
+	}
+	templates := map[string]string{"default": s.Telegram.Templates.Default}
+	for chat, source := range s.Telegram.Templates.Chats {
+		templates["chats."+chat] = source
+	}
+	for category, source := range s.Telegram.Templates.Categories {
+		templates["categories."+category] = source
+	}
+	for name, source := range templates {
+		if _, err := parseMessageTemplate(source); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: telegram.templates.%s: %v", settingsFileName, name, err))
+		}
+	}
+	if s.Schedule != "" {
+		if _, err := parseSchedule(s.Schedule); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: schedule: %v", settingsFileName, err))
+		}
+	}
+	for name, profile := range s.Profiles {
+		if profile.Schedule == "" {
+			continue
+		}
+		if _, err := parseSchedule(profile.Schedule); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: profiles.%s.schedule: %v", settingsFileName, name, err))
+		}
+	}
+	if s.Telegram.Proxy != "" {
+		if _, err := parseProxyURL(s.Telegram.Proxy); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: telegram.proxy: %v", settingsFileName, err))
+		}
+	}
+	if _, err := resolveCutoff(s.Lookback, time.Now(), time.Time{}, time.Time{}); err != nil {
+		problems = append(problems, fmt.Sprintf("%s: lookback: %v", settingsFileName, err))
+	}
+	for feedURL, options := range s.Feeds {
+		if _, exists := dedupStrategies[options.Dedup]; options.Dedup != "" && !exists {
+			problems = append(problems, fmt.Sprintf("%s: feeds.%s: unknown dedup strategy %q", settingsFileName, feedURL, options.Dedup))
+		}
+		if _, err := resolveCutoff(options.Lookback, time.Now(), time.Time{}, time.Time{}); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: feeds.%s: lookback: %v", settingsFileName, feedURL, err))
+		}
+		if options.Proxy != "" {
+			if _, err := parseProxyURL(options.Proxy); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: feeds.%s: %v", settingsFileName, feedURL, err))
+			}
+		}
+		if options.Type == feedTypeJSON && options.JSON.Link == "" {
+			problems = append(problems, fmt.Sprintf("%s: feeds.%s: json feeds need a json.link mapping", settingsFileName, feedURL))
+		}
+	}
+	for domain, rule := range s.Extraction {
+		for field, selector := range map[string]string{"body": rule.Body, "author": rule.Author, "date": rule.Date} {
+			if selector == "" {
+				continue
+			}
+			if _, err := cascadia.Compile(selector); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: extraction.%s.%s: invalid selector %q: %v", settingsFileName, domain, field, selector, err))
+			}
+		}
+	}
+	return problems
+}
+
+// telegramTarget is a chat, and optionally a thread in it, that notifications
+// can go to; Source says which setting it comes from
+type telegramTarget struct {
+	ChatID string
+	Thread string
+	Source string
+}
+
+// telegramTargets lists every chat and thread of every profile's destinations,
+// the routing table and the failover chat
+func telegramTargets(s Settings, defaultChannelID string) ([]telegramTarget, []string) {
+	profiles, err := selectProfiles(s.Profiles, "", defaultChannelID)
+	if err != nil {
+		return nil, []string{err.Error()}
+	}
+
+	var targets []telegramTarget
+	var problems []string
+	for _, profile := range profiles {
+		name := firstNonEmpty(profile.Name, "default")
+		for _, destination := range profile.Destinations {
+			source := fmt.Sprintf("profile %s", name)
+			if destination.Name != "" {
+				source += " destination " + destination.Name
+			}
+			if destination.ChannelID == "" {
+				problems = append(problems, fmt.Sprintf("%s: no channel configured: set TELEGRAM_CHANNEL_ID or channel_id", source))
+				continue
+			}
+			targets = append(targets, telegramTarget{ChatID: destination.ChannelID, Source: source})
+
+			tags := make(map[string]struct{})
+			for _, set := range []map[string]string{profile.Keywords, categoryThreads, destination.Threads, s.Severity.Threads} {
+				for tag := range set {
+					tags[tag] = struct{}{}
+				}
+			}
+			for tag := range s.Programs {
+				tags[tag] = struct{}{}
+			}
+			for tag := range tags {
+				thread, exists := destination.Threads[tag]
+				if !exists {
+					thread, exists = profile.configuredThread(tag)
+				}
+				// The general thread is the chat itself, checked above
+				if exists && thread != "" && thread != keywords["general"] {
+					targets = append(targets, telegramTarget{ChatID: destination.ChannelID, Thread: thread, Source: source + " " + tag})
+				}
+			}
+		}
+	}
+	for tag, route := range s.Routes {
+		targets = append(targets, telegramTarget{ChatID: route.ChatID, Thread: route.Thread, Source: "route " + tag})
+	}
+	if s.Failover.ChatID != "" {
+		targets = append(targets, telegramTarget{ChatID: s.Failover.ChatID, Source: "failover"})
+	}
+	return targets, problems
+}
+
+// validateTelegram checks the bot token and that the bot can post to every
+// target chat and thread
+func validateTelegram(botToken string, targets []telegramTarget) []string {
+	if botToken == "" {
+		return []string{"TELEGRAM_BOT_TOKEN environment variable not set"}
+	}
+
+	client := &http.Client{Transport: httpClient.Transport, Timeout: validateTimeout}
+	if err := callTelegram(client, botToken, "getMe", nil); err != nil {
+		return []string{fmt.Sprintf("Telegram credentials rejected: %v", err)}
+	}
+
+	// Report each chat and thread once, under the first setting naming it
+	sort.Slice(targets, func(i, j int) bool { return targets[i].Source < targets[j].Source })
+	var problems []string
+	checked := make(map[telegramTarget]struct{})
+	for _, target := range targets {
+		key := telegramTarget{ChatID: target.ChatID, Thread: target.Thread}
+		if _, done := checked[key]; done {
+			continue
+		}
+		checked[key] = struct{}{}
+
+		params := map[string]string{"chat_id": target.ChatID, "action": "typing"}
+		if target.Thread != "" {
+			params["message_thread_id"] = target.Thread
+		}
+		if err := callTelegram(client, botToken, "sendChatAction", params); err != nil {
+			problems = append(problems, fmt.Sprintf("%s (chat %s thread %s): %v", target.Source, target.ChatID, target.Thread, err))
+		}
+	}
+	return problems
+}
+
+// callTelegram invokes a Bot API method and turns a non-ok response into an error
+func callTelegram(client *http.Client, botToken, method string, params map[string]string) error {
+	form := url.Values{}
+	for key, value := range params {
+		form.Set(key, value)
+	}
+
+	resp, err := client.PostForm(fmt.Sprintf(telegramMethodTemplate, botToken, method), form)
+	if err != nil {
+		return fmt.Errorf("calling %s: %w", method, stripBotURL(err))
+	}
+	defer resp.Body.Close()
+
+	var result telegramResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("decoding %s response: %w", method, err)
+	}
+	if !result.OK {
+		return fmt.Errorf("%s: %s", method, result.Description)
+	}
+	return nil
+}
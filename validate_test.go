@@ -0,0 +1,82 @@
+package main
+
+import (
+	"os"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestTelegramTargets(t *testing.T) {
+	saved := settings
+	t.Cleanup(func() { settings = saved })
+
+	s := Settings{
+		Profiles: map[string]Profile{
+			"web": {ChannelID: "-1001", Keywords: map[string]string{"xss": "7"}},
+			"mobile": {
+				Keywords: map[string]string{"android": "9"},
+				Destinations: []Destination{
+					{Name: "triage", ChannelID: "-1002", Threads: map[string]string{"android": "11"}},
+					{Name: "broken"},
+				},
+			},
+		},
+		Routes:   map[string]Route{"rce": {ChatID: "-1003", Thread: "4"}},
+		Failover: FailoverSettings{ChatID: "-1004"},
+	}
+	settings = s
+
+	targets, problems := telegramTargets(s, "")
+	var got []string
+	for _, target := range targets {
+		got = append(got, target.ChatID+"/"+target.Thread)
+	}
+	sort.Strings(got)
+	want := []string{"-1001/", "-1001/7", "-1002/", "-1002/11", "-1003/4", "-1004/"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("targets = %v, want %v", got, want)
+	}
+	if len(problems) != 1 || !strings.Contains(problems[0], "destination broken") {
+		t.Errorf("problems = %v, want one about destination broken", problems)
+	}
+}
+
+func TestValidateFeedListSchemes(t *testing.T) {
+	saved := settings
+	t.Cleanup(func() { settings = saved })
+
+	tests := []struct {
+		entry   string
+		imap    string
+		invalid bool
+	}{
+		{"https://example.com/feed", "", false},
+		{"ftp://example.com/feed", "", true},
+		{"imap:Newsletters/tldrsec", "imap.example.com:993", false},
+		{"imap:Newsletters/tldrsec,INBOX", "imap.example.com:993", false},
+		{"imap:INBOX", "imap.example.com", true},
+		{"imap:INBOX", "", true},
+		{"plugin:confluence", "", false},
+		{"plugin:jira", "", true},
+	}
+	for _, tt := range tests {
+		settings = Settings{
+			IMAP:    IMAPSettings{Address: tt.imap, Username: "u", Password: "p"},
+			Plugins: map[string]PluginSettings{"confluence": {Command: []string{"./confluence"}}},
+		}
+		problems := validateFeedList(writeTempFile(t, tt.entry+"\n"))
+		if (len(problems) > 0) != tt.invalid {
+			t.Errorf("%s: problems %v, want invalid=%v", tt.entry, problems, tt.invalid)
+		}
+	}
+}
+
+func writeTempFile(t *testing.T, content string) string {
+	t.Helper()
+	filename := t.TempDir() + "/data.txt"
+	if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filename
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// watchdogGrace is how long past its timeout a feed may run by default
+// before the watchdog gives up on it
+const watchdogGrace = 30 * time.Second
+
+// feedStateMu guards every access to feedValidators, githubWatch and
+// discoveredFeeds, which a fetch abandoned by the watchdog may still reach
+// while the run moves on. Fetches only write them while their context is
+// live, so an abandoned one cannot leak into a later feed or profile.
+var feedStateMu sync.Mutex
+
+// withWatchdog runs a feed fetch and returns its result, or an error once the
+// budget runs out. The fetch's context is cancelled when the watchdog gives
+// up; fetches stuck where they cannot see it finish in the background and
+// their result is discarded.
+func withWatchdog(ctx context.Context, budget time.Duration, fetch func(context.Context) ([]*gofeed.Item, error)) ([]*gofeed.Item, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		items []*gofeed.Item
+		err   error
+	}
+	done := make(chan result, 1)
+	go func() {
+		items, err := fetch(ctx)
+		done <- result{items, err}
+	}()
+
+	timer := time.NewTimer(budget)
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		return r.items, r.err
+	case <-timer.C:
+		return nil, fmt.Errorf("feed watchdog: gave up after %s", budget)
+	}
+}
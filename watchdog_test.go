@@ -0,0 +1,52 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mmcdole/gofeed"
+)
+
+func TestWithWatchdog(t *testing.T) {
+	items := []*gofeed.Item{{Link: "https://example.com/a"}}
+	fetchErr := errors.New("fetch failed")
+
+	tests := []struct {
+		name      string
+		fetch     func(context.Context) ([]*gofeed.Item, error)
+		wantItems int
+		wantErr   bool
+	}{
+		{"fast fetch", func(context.Context) ([]*gofeed.Item, error) { return items, nil }, 1, false},
+		{"fetch error", func(context.Context) ([]*gofeed.Item, error) { return nil, fetchErr }, 0, true},
+		{"fetch ignoring its context", func(context.Context) ([]*gofeed.Item, error) {
+			time.Sleep(time.Second)
+			return items, nil
+		}, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := withWatchdog(context.Background(), 50*time.Millisecond, tt.fetch)
+		if (err != nil) != tt.wantErr || len(got) != tt.wantItems {
+			t.Errorf("%s: got %d items, error %v", tt.name, len(got), err)
+		}
+	}
+}
+
+func TestWithWatchdogCancelsAbandonedFetch(t *testing.T) {
+	cancelled := make(chan struct{})
+	_, err := withWatchdog(context.Background(), 10*time.Millisecond, func(ctx context.Context) ([]*gofeed.Item, error) {
+		<-ctx.Done()
+		close(cancelled)
+		return nil, ctx.Err()
+	})
+	if err == nil {
+		t.Fatal("watchdog did not give up")
+	}
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("abandoned fetch was not cancelled")
+	}
+}
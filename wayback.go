@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	waybackSaveURL = "https://web.archive.org/save/"
+	waybackWebURL  = "https://web.archive.org"
+	waybackTimeout = 90 * time.Second
+)
+
+// WaybackSettings submits matched articles to the Internet Archive's Save Page
+// Now. The optional S3-style keys from archive.org/account/s3.php raise the
+// anonymous rate limit.
+type WaybackSettings struct {
+	Enabled   bool   `yaml:"enabled"`
+	AccessKey string `yaml:"access_key"`
+	SecretKey string `yaml:"secret_key"`
+}
+
+// archiveToWayback asks Save Page Now to capture a page and returns the
+// snapshot URL
+func archiveToWayback(ctx context.Context, link string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, waybackTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, waybackSaveURL+link, nil)
+	if err != nil {
+		return "", fmt.Errorf("creating Wayback request: %w", err)
+	}
+	setUserAgent(req)
+	if settings.Wayback.AccessKey != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("LOW %s:%s", settings.Wayback.AccessKey, settings.Wayback.SecretKey))
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("submitting to Wayback: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		httpErr := &HTTPError{StatusCode: resp.StatusCode}
+		if isRateLimited(resp.StatusCode) {
+			httpErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		}
+		return "", httpErr
+	}
+
+	// The capture is named in Content-Location, or reached by redirect
+	if location := resp.Header.Get("Content-Location"); strings.HasPrefix(location, "/web/") {
+		return waybackWebURL + location, nil
+	}
+	if final := resp.Request.URL.String(); strings.HasPrefix(final, waybackWebURL+"/web/") {
+		return final, nil
+	}
+	return waybackWebURL + "/web/" + link, nil
+}
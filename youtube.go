@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mmcdole/gofeed"
+)
+
+// youtubePrefix marks data.txt shorthand for YouTube's Atom feeds:
+//
+//	youtube:channel:UCxxxxxxxxxxxxxxxxxxxxxx
+//	youtube:playlist:PLxxxxxxxxxxxxxxxx
+const (
+	youtubePrefix    = "youtube:"
+	youtubeFeedBase  = "https://www.youtube.com/feeds/videos.xml"
+	videoCategoryTag = "video"
+)
+
+// expandYouTubeEntry turns a youtube: line into its Atom feed URL
+func expandYouTubeEntry(entry string) ([]string, error) {
+	kind, id, found := strings.Cut(strings.TrimPrefix(entry, youtubePrefix), ":")
+	id = strings.TrimSpace(id)
+	if !found || id == "" || strings.ContainsAny(id, "/?&# ") {
+		return nil, fmt.Errorf("malformed youtube entry: %q", entry)
+	}
+	switch kind {
+	case "channel":
+		return []string{youtubeFeedBase + "?channel_id=" + id}, nil
+	case "playlist":
+		return []string{youtubeFeedBase + "?playlist_id=" + id}, nil
+	}
+	return nil, fmt.Errorf("youtube entry %q: expected channel: or playlist:", entry)
+}
+
+// isYouTubeFeed recognizes channel and playlist Atom feeds
+func isYouTubeFeed(feedURL string) bool {
+	return strings.HasPrefix(feedURL, youtubeFeedBase)
+}
+
+// markVideos tags YouTube entries as videos and fills the description from
+// media:group, which is where YouTube puts it instead of <summary>
+func markVideos(items []*gofeed.Item) {
+	for _, item := range items {
+		item.Categories = append(item.Categories, videoCategoryTag)
+		if item.Description != "" {
+			continue
+		}
+		for _, group := range item.Extensions["media"]["group"] {
+			for _, description := range group.Children["description"] {
+				item.Description = description.Value
+			}
+		}
+	}
+}
+
+// isVideo reports whether an item was tagged by markVideos
+func isVideo(item *gofeed.Item) bool {
+	for _, category := range item.Categories {
+		if category == videoCategoryTag {
+			return true
+		}
+	}
+	return false
+}